@@ -0,0 +1,37 @@
+package iniparser
+
+import "io"
+
+// LoadDefaults parses the INI content read from r like LoadFromReader and
+// marks the keys it sets as defaults. A key keeps the mark until another
+// value is stored for it, whether by a later load, Set, Merge,
+// PatchExisting or a Render that changes it, so IsDefault can tell default
+// settings from overridden ones.
+func (p *Parser) LoadDefaults(r io.Reader) error {
+	p.loadingDefaults = true
+	defer func() { p.loadingDefaults = false }()
+	return p.parse(r)
+}
+
+// IsDefault reports whether the value of key in section was loaded by
+// LoadDefaults and has not been overridden since. An empty section refers to
+// the global keys.
+func (p *Parser) IsDefault(section, key string) bool {
+	return p.defaults[p.resolveSection(section)][key]
+}
+
+// markDefault records whether the value being stored for key in the
+// resolved section comes from LoadDefaults.
+func (p *Parser) markDefault(section, key string) {
+	if !p.loadingDefaults {
+		delete(p.defaults[section], key)
+		return
+	}
+	if p.defaults == nil {
+		p.defaults = make(map[string]map[string]bool)
+	}
+	if p.defaults[section] == nil {
+		p.defaults[section] = make(map[string]bool)
+	}
+	p.defaults[section][key] = true
+}
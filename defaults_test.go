@@ -0,0 +1,76 @@
+package iniparser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadDefaults(strings.NewReader(sampleINI)); err != nil {
+		t.Fatalf("LoadDefaults: %v", err)
+	}
+	if err := p.LoadFromString("[server]\nport = 9090\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Set("", "name", "custom"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		section, key string
+		want         bool
+	}{
+		{"server", "host", true},
+		{"database", "user", true},
+		{"server", "port", false},
+		{"", "name", false},
+		{"server", "missing", false},
+	}
+	for _, tt := range tests {
+		if got := p.IsDefault(tt.section, tt.key); got != tt.want {
+			t.Errorf("IsDefault(%q, %q) = %v, want %v", tt.section, tt.key, got, tt.want)
+		}
+	}
+	if got, _ := p.Get("server", "port"); got != "9090" {
+		t.Errorf("server.port = %q, want the override %q", got, "9090")
+	}
+}
+
+func TestIsDefaultAfterPatchExisting(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadDefaults(strings.NewReader(sampleINI)); err != nil {
+		t.Fatalf("LoadDefaults: %v", err)
+	}
+	overlay := NewParser()
+	if err := overlay.LoadFromString("[server]\nport = 9090\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	p.PatchExisting(overlay)
+
+	if p.IsDefault("server", "port") {
+		t.Error("IsDefault(server, port) = true after PatchExisting overrode it")
+	}
+	if !p.IsDefault("server", "host") {
+		t.Error("IsDefault(server, host) = false for a key left untouched")
+	}
+}
+
+func TestIsDefaultAfterRender(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadDefaults(strings.NewReader("[server]\nhost = localhost\nurl = http://{{.Host}}\n")); err != nil {
+		t.Fatalf("LoadDefaults: %v", err)
+	}
+
+	if err := p.Render(map[string]string{"Host": "example.com"}); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	if !p.IsDefault("server", "host") {
+		t.Error("IsDefault(server, host) = false for a value Render left unchanged")
+	}
+	if p.IsDefault("server", "url") {
+		t.Error("IsDefault(server, url) = true after Render changed it")
+	}
+}
@@ -0,0 +1,150 @@
+package iniparser
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DiffString describes how other differs from p, one line per change. A key
+// only in other is written "+ section.key = value", a key only in p
+// "- section.key = value", and a changed value as a "-" line with the old
+// value followed by a "+" line with the new one. Global keys have no section
+// prefix. Global keys come first, then sections sorted by name, with keys
+// sorted by name; unchanged keys are omitted.
+func (p *Parser) DiffString(other *Parser) string {
+	var b strings.Builder
+	p.diff(other, func(c change) bool {
+		if c.inOld {
+			fmt.Fprintf(&b, "- %s = %s\n", c.name, c.oldValue)
+		}
+		if c.inNew {
+			fmt.Fprintf(&b, "+ %s = %s\n", c.name, c.newValue)
+		}
+		return true
+	})
+	return b.String()
+}
+
+// change is a key whose value differs between two parsers.
+type change struct {
+	name               string
+	oldValue, newValue string
+	inOld, inNew       bool
+}
+
+// diff calls fn for each key that differs between p and other, in the order
+// described by DiffString, until fn returns false.
+func (p *Parser) diff(other *Parser, fn func(change) bool) {
+	for _, section := range unionSections(p, other) {
+		before, _ := p.sectionKeys(section)
+		after, _ := other.sectionKeys(section)
+
+		for _, key := range unionKeys(before, after) {
+			oldValue, inOld := before[key]
+			newValue, inNew := after[key]
+			if inOld && inNew && oldValue == newValue {
+				continue
+			}
+			c := change{qualifiedKey(section, key), oldValue, newValue, inOld, inNew}
+			if !fn(c) {
+				return
+			}
+		}
+	}
+}
+
+// unionSections returns the empty global section followed by the sorted
+// names of every section in p or other.
+func unionSections(p, other *Parser) []string {
+	seen := make(map[string]string)
+	for _, section := range append(p.GetSectionNames(), other.GetSectionNames()...) {
+		seen[section] = ""
+	}
+	delete(seen, "")
+	return append([]string{""}, sortedKeys(seen)...)
+}
+
+// unionKeys returns the sorted keys present in a or b.
+func unionKeys(a, b map[string]string) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ErrNotEqual is returned by AssertEqualsFile when the content differs.
+var ErrNotEqual = errors.New("content differs")
+
+// AssertEqualsFile parses the INI file at path with default settings and
+// compares it with p, ignoring ordering and comments. If they differ, the
+// returned ErrNotEqual error describes the first difference: a section
+// present on one side only, or a key that is missing, unexpected or has
+// another value.
+func (p *Parser) AssertEqualsFile(path string) error {
+	golden := NewParser()
+	if err := golden.ParseFile(path); err != nil {
+		return err
+	}
+
+	for _, section := range p.sections {
+		if !golden.HasSection(section) {
+			return fmt.Errorf("%w from %s: unexpected section [%s]", ErrNotEqual, path, section)
+		}
+	}
+	for _, section := range golden.sections {
+		if !p.HasSection(section) {
+			return fmt.Errorf("%w from %s: missing section [%s]", ErrNotEqual, path, section)
+		}
+	}
+
+	var err error
+	golden.diff(p, func(c change) bool {
+		switch {
+		case !c.inNew:
+			err = fmt.Errorf("%w from %s: missing key %s, want %q", ErrNotEqual, path, c.name, c.oldValue)
+		case !c.inOld:
+			err = fmt.Errorf("%w from %s: unexpected key %s = %q", ErrNotEqual, path, c.name, c.newValue)
+		default:
+			err = fmt.Errorf("%w from %s: %s = %q, want %q", ErrNotEqual, path, c.name, c.newValue, c.oldValue)
+		}
+		return false
+	})
+	return err
+}
+
+// DeltaFrom parses the INI file at baselinePath with default settings and
+// returns a new parser holding only the keys of p whose value differs from
+// the baseline or that the baseline lacks, which suits generating minimal
+// override files. Keys keep the order of p; sections without such keys are
+// left out. Keys only present in the baseline are ignored.
+func (p *Parser) DeltaFrom(baselinePath string) (*Parser, error) {
+	baseline := NewParser()
+	if err := baseline.ParseFile(baselinePath); err != nil {
+		return nil, err
+	}
+
+	delta := NewParser()
+	add := func(section string, keys map[string]string, order []string) {
+		for _, key := range order {
+			value := keys[key]
+			if old, ok := baseline.Get(section, key); ok && old == value {
+				continue
+			}
+			delta.setValue(section, key, value)
+		}
+	}
+	add("", p.globalKeys, p.globalOrder)
+	for _, section := range p.sections {
+		add(section, p.data[section], p.keyOrder[section])
+	}
+	return delta, nil
+}
@@ -0,0 +1,90 @@
+package iniparser
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffString(t *testing.T) {
+	before := loadSample(t)
+
+	after := loadSample(t)
+	if err := after.Set("server", "port", "9090"); err != nil {
+		t.Fatal(err)
+	}
+	if err := after.Set("cache", "size", "64"); err != nil {
+		t.Fatal(err)
+	}
+	if err := after.Set("", "env", "prod"); err != nil {
+		t.Fatal(err)
+	}
+	if !after.deleteKey("database", "password") {
+		t.Fatal("password not deleted")
+	}
+
+	want := `+ env = prod
++ cache.size = 64
+- database.password = secret
+- server.port = 8080
++ server.port = 9090
+`
+	if got := before.DiffString(after); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := before.DiffString(loadSample(t)); got != "" {
+		t.Errorf("equal parsers: got %q, want empty diff", got)
+	}
+}
+
+func TestAssertEqualsFile(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"same.ini":  "# reordered\nname = demo\n[database]\npassword = secret\nuser = admin\n[server]\nport = 8080\nhost = localhost\n",
+		"other.ini": sampleINI + "\n[server]\nport = 9090\n",
+	})
+	p := loadSample(t)
+
+	if err := p.AssertEqualsFile(filepath.Join(dir, "same.ini")); err != nil {
+		t.Errorf("matching file: %v", err)
+	}
+
+	err := p.AssertEqualsFile(filepath.Join(dir, "other.ini"))
+	if !errors.Is(err, ErrNotEqual) {
+		t.Fatalf("differing file: got %v, want %v", err, ErrNotEqual)
+	}
+	if !strings.Contains(err.Error(), `server.port = "8080", want "9090"`) {
+		t.Errorf("error %q does not describe the difference", err)
+	}
+}
+
+func TestDeltaFrom(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"base.ini": sampleINI + "\n[legacy]\nmode = old\n",
+	})
+	p := loadSample(t)
+	for _, kv := range [][3]string{
+		{"server", "port", "9090"},
+		{"cache", "size", "64"},
+		{"", "env", "prod"},
+	} {
+		if err := p.Set(kv[0], kv[1], kv[2]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	delta, err := p.DeltaFrom(filepath.Join(dir, "base.ini"))
+	if err != nil {
+		t.Fatalf("DeltaFrom: %v", err)
+	}
+
+	want := "env = prod\n\n[server]\nport = 9090\n\n[cache]\nsize = 64\n"
+	if got := delta.ToString(); got != want {
+		t.Errorf("delta = %q, want %q", got, want)
+	}
+
+	if _, err := p.DeltaFrom(filepath.Join(dir, "missing.ini")); err == nil {
+		t.Error("DeltaFrom(missing file) succeeded")
+	}
+}
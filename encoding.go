@@ -0,0 +1,195 @@
+package iniparser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// ErrInvalidEncoding is returned when a percent-encoded value is malformed.
+var ErrInvalidEncoding = errors.New("invalid percent encoding")
+
+// SetEncodeControlChars enables percent-encoding of values. When enabled,
+// output replaces every control byte (0x00-0x1F and 0x7F) and the percent
+// sign itself with %XX, using two uppercase hex digits, and parsing decodes
+// %XX sequences back. Values holding tabs, newlines or NUL bytes then
+// survive a round trip unchanged.
+func (p *Parser) SetEncodeControlChars(enable bool) {
+	p.encodeControl = enable
+}
+
+func encodeControlChars(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x20 || c == 0x7f || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func decodeControlChars(s string) (string, error) {
+	if !strings.Contains(s, "%") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+3 > len(s) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidEncoding, s[i:])
+		}
+		c, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("%w: %q", ErrInvalidEncoding, s[i:i+3])
+		}
+		b.WriteByte(byte(c))
+		i += 2
+	}
+	return b.String(), nil
+}
+
+// SetUnquoteValues makes parsing remove the double quotes around a value
+// written as "..." and decode the escape sequences inside it as
+// UnescapeValue does, so "line1\nline2" yields two lines. Unquoted values
+// are kept as written. GetRaw still returns the text as written.
+//
+// Writing then quotes the values that would not read back unchanged
+// otherwise: those with leading or trailing whitespace, a line break, the
+// key delimiter, a comment prefix or a leading double quote. Other values
+// are written as they are.
+func (p *Parser) SetUnquoteValues(unquote bool) {
+	p.unquote = unquote
+}
+
+// needsQuotes reports whether value, written in section, has to be quoted
+// to be read back unchanged by a parser with SetUnquoteValues enabled.
+func (p *Parser) needsQuotes(section, value string) bool {
+	if value == "" {
+		return false
+	}
+	if strings.TrimSpace(value) != value || strings.ContainsAny(value, "\r\n") ||
+		value[0] == '"' || strings.Contains(value, p.keyDelimiter(section)) {
+		return true
+	}
+	for _, prefix := range p.sectionCommentPrefixes(section) {
+		if prefix != "" && strings.Contains(strings.ToLower(value), strings.ToLower(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+func unquoteValue(value string) string {
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return value
+	}
+	return UnescapeValue(value[1 : len(value)-1])
+}
+
+// valueEscaper implements EscapeValue.
+var valueEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+	`"`, `\"`,
+	"=", `\=`,
+)
+
+// EscapeValue escapes s for embedding between the double quotes of a
+// single-line INI value. Backslashes, line feeds, carriage returns, tabs,
+// double quotes and the "=" delimiter are written as \\, \n, \r, \t, \" and
+// \= respectively. Escapes are only decoded in quoted values, so the result
+// must be wrapped in double quotes and read by a parser with
+// SetUnquoteValues enabled; an unquoted escaped value is kept as written.
+// UnescapeValue reverses it.
+func EscapeValue(s string) string {
+	return valueEscaper.Replace(s)
+}
+
+// UnescapeValue reverses EscapeValue. Unknown escape sequences and a trailing
+// lone backslash are kept as they are.
+func UnescapeValue(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '\\', '"', '=':
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// decodeBOM returns a reader of UTF-8 text for r. A leading UTF-8 byte order
+// mark is dropped, and input starting with a UTF-16 little or big endian
+// byte order mark is decoded to UTF-8. Input without a byte order mark is
+// assumed to be UTF-8.
+func decodeBOM(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(len(bomUTF8))
+
+	var order binary.ByteOrder
+	switch {
+	case bytes.HasPrefix(head, bomUTF8):
+		br.Discard(len(bomUTF8))
+		return br, nil
+	case bytes.HasPrefix(head, bomUTF16LE):
+		order = binary.LittleEndian
+	case bytes.HasPrefix(head, bomUTF16BE):
+		order = binary.BigEndian
+	default:
+		return br, nil
+	}
+
+	content, err := io.ReadAll(br)
+	if err != nil {
+		return nil, err
+	}
+	content = content[len(bomUTF16LE):]
+	if len(content)%2 != 0 {
+		return nil, fmt.Errorf("%w: truncated UTF-16 input", ErrUnsupportedEncoding)
+	}
+
+	units := make([]uint16, len(content)/2)
+	for i := range units {
+		units[i] = order.Uint16(content[2*i:])
+	}
+	return strings.NewReader(string(utf16.Decode(units))), nil
+}
@@ -0,0 +1,168 @@
+package iniparser
+
+import (
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestEncodeControlChars(t *testing.T) {
+	value := "a\tb\x00c%d"
+
+	p := NewParser()
+	p.SetEncodeControlChars(true)
+	if err := p.Set("s", "blob", value); err != nil {
+		t.Fatal(err)
+	}
+
+	out := p.ToString()
+	if want := "blob = a%09b%00c%25d\n"; !strings.Contains(out, want) {
+		t.Errorf("output %q does not contain %q", out, want)
+	}
+
+	loaded := NewParser()
+	loaded.SetEncodeControlChars(true)
+	if err := loaded.LoadFromString(out); err != nil {
+		t.Fatalf("LoadFromString: %v", err)
+	}
+	if got, _ := loaded.Get("s", "blob"); got != value {
+		t.Errorf("got %q, want %q", got, value)
+	}
+}
+
+func TestDecodeControlCharsInvalid(t *testing.T) {
+	for _, input := range []string{"[s]\nk = 100%", "[s]\nk = %zz", "[s]\nk = a%4"} {
+		p := NewParser()
+		p.SetEncodeControlChars(true)
+		if err := p.LoadFromString(input); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("LoadFromString(%q): got %v, want %v", input, err, ErrInvalidEncoding)
+		}
+	}
+}
+
+func TestEscapeValue(t *testing.T) {
+	tests := []struct{ value, escaped string }{
+		{"plain", "plain"},
+		{"line1\nline2", `line1\nline2`},
+		{"a\tb\r", `a\tb\r`},
+		{`say "hi"`, `say \"hi\"`},
+		{"a=b", `a\=b`},
+		{`C:\new`, `C:\\new`},
+		{`\n`, `\\n`},
+	}
+
+	for _, tt := range tests {
+		if got := EscapeValue(tt.value); got != tt.escaped {
+			t.Errorf("EscapeValue(%q) = %q, want %q", tt.value, got, tt.escaped)
+		}
+		if got := UnescapeValue(tt.escaped); got != tt.value {
+			t.Errorf("UnescapeValue(%q) = %q, want %q", tt.escaped, got, tt.value)
+		}
+		if strings.ContainsAny(EscapeValue(tt.value), "\n\r\t") {
+			t.Errorf("EscapeValue(%q) is not a single line", tt.value)
+		}
+	}
+
+	if got := UnescapeValue(`\x trailing\`); got != `\x trailing\` {
+		t.Errorf("unknown escapes: got %q", got)
+	}
+}
+
+func encodeUTF16(s string, order binary.AppendByteOrder, bom []byte) []byte {
+	b := append([]byte(nil), bom...)
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = order.AppendUint16(b, u)
+	}
+	return b
+}
+
+func TestByteOrderMarks(t *testing.T) {
+	const content = "[server]\nname = café\n"
+
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{"UTF-8", append([]byte{0xEF, 0xBB, 0xBF}, content...)},
+		{"UTF-16 LE", encodeUTF16(content, binary.LittleEndian, []byte{0xFF, 0xFE})},
+		{"UTF-16 BE", encodeUTF16(content, binary.BigEndian, []byte{0xFE, 0xFF})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser()
+			if err := p.ParseBytes(tt.input); err != nil {
+				t.Fatalf("ParseBytes: %v", err)
+			}
+			if got, _ := p.Get("server", "name"); got != "café" {
+				t.Errorf("got %q, want %q", got, "café")
+			}
+		})
+	}
+
+	truncated := encodeUTF16(content, binary.LittleEndian, []byte{0xFF, 0xFE})
+	truncated = truncated[:len(truncated)-1]
+	if err := NewParser().ParseBytes(truncated); !errors.Is(err, ErrUnsupportedEncoding) {
+		t.Errorf("truncated UTF-16: got %v, want %v", err, ErrUnsupportedEncoding)
+	}
+}
+
+func TestQuotedValuesRoundTrip(t *testing.T) {
+	p := NewParser()
+	p.SetUnquoteValues(true)
+	values := map[string]string{
+		"padded": "  leading spaces",
+		"query":  "a=b",
+		"note":   "see #4",
+		"quoted": `"x" marks`,
+		"lines":  "one\ntwo",
+		"plain":  "localhost",
+	}
+	for key, value := range values {
+		if err := p.Set("s", key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := p.ToString()
+	for _, line := range []string{
+		`padded = "  leading spaces"`,
+		`query = "a\=b"`,
+		`note = "see #4"`,
+		`quoted = "\"x\" marks"`,
+		`lines = "one\ntwo"`,
+		"plain = localhost",
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("output lacks %q:\n%s", line, out)
+		}
+	}
+
+	back := NewParser()
+	back.SetUnquoteValues(true)
+	if err := back.LoadFromString(out); err != nil {
+		t.Fatal(err)
+	}
+	if got := back.GetSections()["s"]; !reflect.DeepEqual(got, values) {
+		t.Errorf("read back %q, want %q", got, values)
+	}
+}
+
+func TestEscapeValueNeedsQuotes(t *testing.T) {
+	const value = "a\tb=c \"d\""
+	p := NewParser()
+	p.SetUnquoteValues(true)
+	if err := p.LoadFromString("[s]\nquoted = \"" + EscapeValue(value) + "\"\nplain = " + EscapeValue(value) + "\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := p.Get("s", "quoted"); got != value {
+		t.Errorf("quoted = %q, want %q", got, value)
+	}
+	if got, _ := p.Get("s", "plain"); got != EscapeValue(value) {
+		t.Errorf("plain = %q, want it kept as written", got)
+	}
+}
@@ -0,0 +1,175 @@
+package iniparser
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ToTOML renders the parser as TOML. Global keys become top-level keys and
+// each section becomes a table, in section order with keys in the order they
+// were first set. Every value is written as a TOML string, even when it looks
+// like a number or boolean, so no value changes meaning during migration.
+// Keys and table names that are not bare TOML keys are quoted. It fails on
+// content that is not valid UTF-8, which TOML cannot represent.
+func (p *Parser) ToTOML() (string, error) {
+	var b strings.Builder
+
+	for _, key := range sortedKeys(p.globalKeys) {
+		if err := writeTOMLPair(&b, key, p.globalKeys[key]); err != nil {
+			return "", err
+		}
+	}
+
+	for _, section := range p.sections {
+		if !utf8.ValidString(section) {
+			return "", fmt.Errorf("%w: section %q is not valid UTF-8", ErrInvalidValue, section)
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[%s]\n", tomlKey(section))
+		for _, key := range p.keyOrder[section] {
+			if err := writeTOMLPair(&b, key, p.data[section][key]); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return b.String(), nil
+}
+
+func writeTOMLPair(b *strings.Builder, key, value string) error {
+	if !utf8.ValidString(key) || !utf8.ValidString(value) {
+		return fmt.Errorf("%w: key %q is not valid UTF-8", ErrInvalidValue, key)
+	}
+	fmt.Fprintf(b, "%s = %s\n", tomlKey(key), tomlString(value))
+	return nil
+}
+
+// tomlKey returns key unchanged if it is a bare TOML key, or quoted otherwise.
+func tomlKey(key string) string {
+	if key == "" {
+		return `""`
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return tomlString(key)
+		}
+	}
+	return key
+}
+
+// tomlString quotes s as a TOML basic string.
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// ToDotEnv renders the parser as a .env file with one NAME=value line per
+// key. Global keys are named after the key alone and section keys after the
+// section and key joined with sectionSep, typically "_". Names are
+// uppercased and every character other than a letter, digit or underscore
+// becomes an underscore, so [db.main] pool-size is DB_MAIN_POOL_SIZE. Values
+// are double-quoted, with \, ", $ and line breaks escaped, unless they only
+// hold characters that need no quoting. It fails if a name starts with a
+// digit or two keys map to the same name.
+func (p *Parser) ToDotEnv(sectionSep string) (string, error) {
+	var b strings.Builder
+	seen := make(map[string]string)
+
+	write := func(section, key, value string) error {
+		name := dotEnvName(key)
+		if section != "" {
+			name = dotEnvName(section + sectionSep + key)
+		}
+		qualified := qualifiedKey(section, key)
+		if name == "" || name[0] >= '0' && name[0] <= '9' {
+			return fmt.Errorf("%w: %s gives the variable name %q", ErrInvalidName, qualified, name)
+		}
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("%w: %s and %s both map to %s", ErrKeyCollision, other, qualified, name)
+		}
+		seen[name] = qualified
+		fmt.Fprintf(&b, "%s=%s\n", name, dotEnvValue(value))
+		return nil
+	}
+
+	for _, key := range p.globalOrder {
+		if err := write("", key, p.globalKeys[key]); err != nil {
+			return "", err
+		}
+	}
+	for _, section := range p.sections {
+		for _, key := range p.keyOrder[section] {
+			if err := write(section, key, p.data[section][key]); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return b.String(), nil
+}
+
+// dotEnvName turns name into an environment variable name.
+func dotEnvName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// dotEnvValue returns value as is if it is safe unquoted in a .env file, or
+// double-quoted otherwise.
+func dotEnvValue(value string) string {
+	plain := value != ""
+	for _, r := range value {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_-.,:/@%+", r)) {
+			plain = false
+			break
+		}
+	}
+	if plain {
+		return value
+	}
+	return `"` + dotEnvEscaper.Replace(value) + `"`
+}
+
+var dotEnvEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"$", `\$`,
+	"\n", `\n`,
+	"\r", `\r`,
+)
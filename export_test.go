@@ -0,0 +1,86 @@
+package iniparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestToTOML(t *testing.T) {
+	p := NewParser()
+	err := p.LoadFromString(`name = demo
+[server]
+host = localhost
+port = 8080
+[server.eu]
+path = C:\app "main"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `name = "demo"
+
+[server]
+host = "localhost"
+port = "8080"
+
+["server.eu"]
+path = "C:\\app \"main\""
+`
+	got, err := p.ToTOML()
+	if err != nil {
+		t.Fatalf("ToTOML: %v", err)
+	}
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestToTOMLInvalidUTF8(t *testing.T) {
+	p := NewParser()
+	if err := p.Set("s", "k", "\xff"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := p.ToTOML(); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("got %v, want %v", err, ErrInvalidValue)
+	}
+}
+
+func TestToDotEnv(t *testing.T) {
+	p := NewParser()
+	err := p.LoadFromString(`name = demo
+[server]
+host = localhost
+port = 8080
+[db.main]
+pool-size = 10
+dsn = user:pa$$ "x"@host
+greeting = hello world
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `NAME=demo
+SERVER_HOST=localhost
+SERVER_PORT=8080
+DB_MAIN_POOL_SIZE=10
+DB_MAIN_DSN="user:pa\$\$ \"x\"@host"
+DB_MAIN_GREETING="hello world"
+`
+	got, err := p.ToDotEnv("_")
+	if err != nil {
+		t.Fatalf("ToDotEnv: %v", err)
+	}
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	if err := p.Set("server", "Host", "other"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.ToDotEnv("_"); !errors.Is(err, ErrKeyCollision) {
+		t.Errorf("colliding names: got %v, want %v", err, ErrKeyCollision)
+	}
+}
@@ -0,0 +1,43 @@
+package iniparser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidFileReference is returned for a file reference that cannot be
+// resolved.
+var ErrInvalidFileReference = errors.New("invalid file reference")
+
+// SetFileReferencePrefix makes values starting with prefix, such as
+// "@file:", be replaced at parse time by the contents of the file they name,
+// which keeps secrets out of the INI file. The path is resolved against the
+// directory of the parsed file, or the working directory when parsing from a
+// string or reader, and must stay inside it: absolute paths and paths
+// leaving it through ".." are rejected. A single trailing line break is
+// dropped from the contents. An empty prefix, the default, disables this.
+func (p *Parser) SetFileReferencePrefix(prefix string) {
+	p.filePrefix = prefix
+}
+
+func (p *Parser) resolveFileReference(dir, value string) (string, error) {
+	if p.filePrefix == "" || !strings.HasPrefix(value, p.filePrefix) {
+		return value, nil
+	}
+
+	name := strings.TrimSpace(strings.TrimPrefix(value, p.filePrefix))
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("%w: %q is outside of the configuration directory", ErrInvalidFileReference, name)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidFileReference, err)
+	}
+
+	s := strings.TrimSuffix(string(content), "\n")
+	return strings.TrimSuffix(s, "\r"), nil
+}
@@ -0,0 +1,47 @@
+package iniparser
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileReference(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"secret.txt":  "s3cr3t\n",
+		"ok.ini":      "[db]\npassword = @file:secret.txt\nuser = admin\n",
+		"missing.ini": "[db]\npassword = @file:nope.txt\n",
+		"escape.ini":  "[db]\npassword = @file:../secret.txt\n",
+	})
+
+	t.Run("loaded from file", func(t *testing.T) {
+		p := NewParser()
+		p.SetFileReferencePrefix("@file:")
+		if err := p.ParseFile(filepath.Join(dir, "ok.ini")); err != nil {
+			t.Fatalf("ParseFile: %v", err)
+		}
+		if got, _ := p.Get("db", "password"); got != "s3cr3t" {
+			t.Errorf("got %q, want %q", got, "s3cr3t")
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		p := NewParser()
+		if err := p.ParseFile(filepath.Join(dir, "ok.ini")); err != nil {
+			t.Fatalf("ParseFile: %v", err)
+		}
+		if got, _ := p.Get("db", "password"); got != "@file:secret.txt" {
+			t.Errorf("got %q, want the literal reference", got)
+		}
+	})
+
+	for _, name := range []string{"missing.ini", "escape.ini"} {
+		t.Run(name, func(t *testing.T) {
+			p := NewParser()
+			p.SetFileReferencePrefix("@file:")
+			if err := p.ParseFile(filepath.Join(dir, name)); !errors.Is(err, ErrInvalidFileReference) {
+				t.Errorf("got %v, want %v", err, ErrInvalidFileReference)
+			}
+		})
+	}
+}
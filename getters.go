@@ -0,0 +1,204 @@
+package iniparser
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GetTrimmed returns the value of key in section with every leading and
+// trailing character contained in cutset removed, as strings.Trim does.
+func (p *Parser) GetTrimmed(section, key, cutset string) (string, bool) {
+	value, ok := p.Get(section, key)
+	if !ok {
+		return "", false
+	}
+	return strings.Trim(value, cutset), true
+}
+
+// GetMany looks up several keys of section at once. It returns the keys that
+// were found with their values, and the keys that were missing in the order
+// they were requested.
+func (p *Parser) GetMany(section string, keys ...string) (map[string]string, []string) {
+	found := make(map[string]string, len(keys))
+	var missing []string
+	for _, key := range keys {
+		if value, ok := p.Get(section, key); ok {
+			found[key] = value
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	return found, missing
+}
+
+// GetEnum returns the value of key in section matched case-insensitively
+// against allowed, using the spelling found in allowed. It fails when the key
+// is missing or its value is not one of the allowed options.
+func (p *Parser) GetEnum(section, key string, allowed []string) (string, error) {
+	value, ok := p.Get(section, key)
+	if !ok {
+		return "", fmt.Errorf("%w: [%s] %s", ErrKeyNotFound, section, key)
+	}
+	for _, option := range allowed {
+		if strings.EqualFold(value, option) {
+			return option, nil
+		}
+	}
+	return "", fmt.Errorf("%w: [%s] %s = %q, valid options: %s",
+		ErrInvalidValue, section, key, value, strings.Join(allowed, ", "))
+}
+
+// KeysByValue returns the keys of section sorted by their values, with ties
+// broken by key name. It reports false when the section does not exist.
+func (p *Parser) KeysByValue(section string) ([]string, bool) {
+	keys, ok := p.sectionKeys(section)
+	if !ok {
+		return nil, false
+	}
+
+	names := sortedKeys(keys)
+	sort.SliceStable(names, func(i, j int) bool {
+		return keys[names[i]] < keys[names[j]]
+	})
+	return names, true
+}
+
+// GetBytes decodes the value of key in section using encoding, which must be
+// "base64" (standard, padded alphabet) or "hex".
+func (p *Parser) GetBytes(section, key, encoding string) ([]byte, error) {
+	value, ok := p.Get(section, key)
+	if !ok {
+		return nil, fmt.Errorf("%w: [%s] %s", ErrKeyNotFound, section, key)
+	}
+
+	var decoded []byte
+	var err error
+	switch encoding {
+	case "base64":
+		decoded, err = base64.StdEncoding.DecodeString(value)
+	case "hex":
+		decoded, err = hex.DecodeString(value)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedEncoding, encoding)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: [%s] %s is not valid %s: %v", ErrInvalidValue, section, key, encoding, err)
+	}
+	return decoded, nil
+}
+
+// GetDotted returns the value addressed by path, written "section.key" or
+// just "key" for a global key. The path is split on its last dot, so section
+// names may contain dots but keys containing a dot cannot be reached this
+// way: "a.b.c" means key "c" in section "a.b".
+func (p *Parser) GetDotted(path string) (string, bool) {
+	return p.Get(splitQualifiedKey(path))
+}
+
+// GetFirst returns the value of the first of keys that exists in section,
+// which suits deprecated aliases such as GetFirst("ui", "color", "colour").
+func (p *Parser) GetFirst(section string, keys ...string) (string, bool) {
+	for _, key := range keys {
+		if value, ok := p.Get(section, key); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// GetMapped returns fn applied to the value of key in section, such as a
+// function expanding a leading ~ to the home directory. The stored value is
+// left unchanged, and fn is not called if the key is missing.
+func (p *Parser) GetMapped(section, key string, fn func(string) string) (string, bool) {
+	value, ok := p.Get(section, key)
+	if !ok {
+		return "", false
+	}
+	return fn(value), true
+}
+
+// GetJoined returns the value of baseKey in baseSection joined to the value
+// of key in section with sep, so that [paths] root = /opt and [app] dir = bin
+// give "/opt/bin" with sep "/". A sep already ending the base or starting
+// the value is not repeated. It reports false if either value is missing.
+func (p *Parser) GetJoined(section, key, baseSection, baseKey, sep string) (string, bool) {
+	base, ok := p.Get(baseSection, baseKey)
+	if !ok {
+		return "", false
+	}
+	value, ok := p.Get(section, key)
+	if !ok {
+		return "", false
+	}
+	return strings.TrimSuffix(base, sep) + sep + strings.TrimPrefix(value, sep), true
+}
+
+// GetList returns the value of key in section as a list. A value wrapped in
+// brackets, such as [80, 443, 8080], is split on commas with each element
+// trimmed of whitespace, and [] gives an empty list. Any other value is
+// returned as a single-element list.
+func (p *Parser) GetList(section, key string) ([]string, bool) {
+	value, ok := p.Get(section, key)
+	if !ok {
+		return nil, false
+	}
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		return []string{value}, true
+	}
+
+	inner := strings.TrimSpace(value[1 : len(value)-1])
+	if inner == "" {
+		return []string{}, true
+	}
+	return p.splitList(inner), true
+}
+
+// GetStringSlice returns the value of key in section split on commas, with
+// each element trimmed of whitespace. An empty value gives an empty slice.
+func (p *Parser) GetStringSlice(section, key string) ([]string, bool) {
+	value, ok := p.Get(section, key)
+	if !ok {
+		return nil, false
+	}
+	if strings.TrimSpace(value) == "" {
+		return []string{}, true
+	}
+	return p.splitList(value), true
+}
+
+// SetDropTrailingEmpty makes GetList and GetStringSlice drop the empty
+// element left by a trailing comma, so "a,b,c," gives three elements rather
+// than four with an empty last one.
+func (p *Parser) SetDropTrailingEmpty(drop bool) {
+	p.dropTrailingEmpty = drop
+}
+
+// splitList splits s on commas and trims each element.
+func (p *Parser) splitList(s string) []string {
+	list := strings.Split(s, ",")
+	for i, item := range list {
+		list[i] = strings.TrimSpace(item)
+	}
+	if p.dropTrailingEmpty && len(list) > 1 && list[len(list)-1] == "" {
+		list = list[:len(list)-1]
+	}
+	return list
+}
+
+// AllKeys returns the fully qualified name of every key, written
+// section+sep+key, or just key for global keys, in the order of Entries.
+func (p *Parser) AllKeys(sep string) []string {
+	entries := p.Entries()
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		if e.Section == "" {
+			keys[i] = e.Key
+		} else {
+			keys[i] = e.Section + sep + e.Key
+		}
+	}
+	return keys
+}
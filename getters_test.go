@@ -0,0 +1,281 @@
+package iniparser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetTrimmed(t *testing.T) {
+	p := NewParser()
+	if err := p.Set("s", "wrapped", "<x>"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := p.GetTrimmed("s", "wrapped", "<>"); !ok || got != "x" {
+		t.Errorf("got (%q, %v), want (%q, true)", got, ok, "x")
+	}
+	if got, ok := p.GetTrimmed("s", "missing", "<>"); ok || got != "" {
+		t.Errorf("got (%q, %v), want (\"\", false)", got, ok)
+	}
+}
+
+func TestGetMany(t *testing.T) {
+	p := loadSample(t)
+
+	found, missing := p.GetMany("server", "host", "user", "port", "timeout")
+
+	wantFound := map[string]string{"host": "localhost", "port": "8080"}
+	if !reflect.DeepEqual(found, wantFound) {
+		t.Errorf("found = %v, want %v", found, wantFound)
+	}
+	wantMissing := []string{"user", "timeout"}
+	if !reflect.DeepEqual(missing, wantMissing) {
+		t.Errorf("missing = %v, want %v", missing, wantMissing)
+	}
+}
+
+func TestGetEnum(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("[app]\nmode = Prod\nlevel = verbose\n"); err != nil {
+		t.Fatal(err)
+	}
+	allowed := []string{"dev", "staging", "prod"}
+
+	if got, err := p.GetEnum("app", "mode", allowed); err != nil || got != "prod" {
+		t.Errorf("got (%q, %v), want (%q, nil)", got, err, "prod")
+	}
+
+	_, err := p.GetEnum("app", "level", allowed)
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidValue)
+	}
+	if !strings.Contains(err.Error(), "dev, staging, prod") {
+		t.Errorf("error %q does not list the valid options", err)
+	}
+
+	if _, err := p.GetEnum("app", "missing", allowed); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("got %v, want %v", err, ErrKeyNotFound)
+	}
+}
+
+func TestKeysByValue(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("[rank]\nc = 2\na = 3\nd = 1\nb = 2\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := p.KeysByValue("rank")
+	want := []string{"d", "b", "c", "a"}
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("got (%v, %v), want (%v, true)", got, ok, want)
+	}
+
+	if _, ok := p.KeysByValue("missing"); ok {
+		t.Error("missing section: got ok = true")
+	}
+}
+
+func TestGetBytes(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("[keys]\nb64 = aGVsbG8=\nhex = 68656c6c6f\nbad = zz!\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key, encoding string
+		want          []byte
+		err           error
+	}{
+		{"b64", "base64", []byte("hello"), nil},
+		{"hex", "hex", []byte("hello"), nil},
+		{"bad", "base64", nil, ErrInvalidValue},
+		{"bad", "hex", nil, ErrInvalidValue},
+		{"hex", "base32", nil, ErrUnsupportedEncoding},
+		{"missing", "hex", nil, ErrKeyNotFound},
+	}
+
+	for _, tt := range tests {
+		got, err := p.GetBytes("keys", tt.key, tt.encoding)
+		if !errors.Is(err, tt.err) || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetBytes(%q, %q) = (%q, %v), want (%q, %v)", tt.key, tt.encoding, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestGetDotted(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("name = demo\n[server]\nport = 8080\nlog.level = debug\n[server.eu]\nport = 9090\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"server.port", "8080", true},
+		{"name", "demo", true},
+		{"server.eu.port", "9090", true},
+		{"server.log.level", "", false},
+		{"server.missing", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := p.GetDotted(tt.path)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("GetDotted(%q) = (%q, %v), want (%q, %v)", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGetFirst(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("[ui]\ncolour = red\ntheme = dark\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		keys []string
+		want string
+		ok   bool
+	}{
+		{[]string{"theme", "colour"}, "dark", true},
+		{[]string{"color", "colour"}, "red", true},
+		{[]string{"color", "tint"}, "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := p.GetFirst("ui", tt.keys...)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("GetFirst(%v) = (%q, %v), want (%q, %v)", tt.keys, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGetMapped(t *testing.T) {
+	p := loadSample(t)
+	calls := 0
+	upper := func(s string) string {
+		calls++
+		return strings.ToUpper(s)
+	}
+
+	if got, ok := p.GetMapped("server", "host", upper); !ok || got != "LOCALHOST" {
+		t.Errorf("GetMapped(host) = (%q, %v), want (%q, true)", got, ok, "LOCALHOST")
+	}
+	if got, _ := p.Get("server", "host"); got != "localhost" {
+		t.Errorf("stored value changed to %q", got)
+	}
+	if got, ok := p.GetMapped("server", "missing", upper); ok || got != "" {
+		t.Errorf("GetMapped(missing) = (%q, %v), want (\"\", false)", got, ok)
+	}
+	if calls != 1 {
+		t.Errorf("fn called %d times, want 1", calls)
+	}
+}
+
+func TestGetJoined(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("[paths]\nroot = /opt\nslashed = /srv/\n[app]\ndir = bin\nlogs = /log\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key, baseKey string
+		want         string
+		ok           bool
+	}{
+		{"dir", "root", "/opt/bin", true},
+		{"logs", "slashed", "/srv/log", true},
+		{"dir", "missing", "", false},
+		{"missing", "root", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := p.GetJoined("app", tt.key, "paths", tt.baseKey, "/")
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("GetJoined(%s, %s) = (%q, %v), want (%q, %v)", tt.key, tt.baseKey, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGetList(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("[net]\nports = [80, 443 ,8080]\nnone = []\nhost = localhost\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key  string
+		want []string
+		ok   bool
+	}{
+		{"ports", []string{"80", "443", "8080"}, true},
+		{"none", []string{}, true},
+		{"host", []string{"localhost"}, true},
+		{"missing", nil, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := p.GetList("net", tt.key)
+		if !reflect.DeepEqual(got, tt.want) || ok != tt.ok {
+			t.Errorf("GetList(%q) = (%#v, %v), want (%#v, %v)", tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGetStringSlice(t *testing.T) {
+	p := NewParser()
+	p.SetAllowEmptyValues(true)
+	if err := p.LoadFromString("[net]\nhosts = a, b ,c\nempty =\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key  string
+		want []string
+		ok   bool
+	}{
+		{"hosts", []string{"a", "b", "c"}, true},
+		{"empty", []string{}, true},
+		{"missing", nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := p.GetStringSlice("net", tt.key)
+		if !reflect.DeepEqual(got, tt.want) || ok != tt.ok {
+			t.Errorf("GetStringSlice(%q) = (%#v, %v), want (%#v, %v)", tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSetDropTrailingEmpty(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("[net]\nhosts = a,b,c,\nports = [80, 443, ]\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, drop := range []bool{false, true} {
+		p.SetDropTrailingEmpty(drop)
+		hosts, ports := []string{"a", "b", "c"}, []string{"80", "443"}
+		if !drop {
+			hosts, ports = append(hosts, ""), append(ports, "")
+		}
+
+		if got, _ := p.GetStringSlice("net", "hosts"); !reflect.DeepEqual(got, hosts) {
+			t.Errorf("drop=%v: GetStringSlice = %q, want %q", drop, got, hosts)
+		}
+		if got, _ := p.GetList("net", "ports"); !reflect.DeepEqual(got, ports) {
+			t.Errorf("drop=%v: GetList = %q, want %q", drop, got, ports)
+		}
+	}
+}
+
+func TestAllKeys(t *testing.T) {
+	p := loadSample(t)
+
+	want := []string{"name", "server/host", "server/port", "database/user", "database/password"}
+	if got := p.AllKeys("/"); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
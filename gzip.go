@@ -0,0 +1,74 @@
+package iniparser
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotGzipINI is returned when a gzip path does not end in ".ini.gz".
+var ErrNotGzipINI = errors.New(`path must end in ".ini.gz"`)
+
+func checkGzipPath(path string) error {
+	if !strings.HasSuffix(path, ".ini.gz") {
+		return fmt.Errorf("%w: %s", ErrNotGzipINI, path)
+	}
+	return nil
+}
+
+// ParseFileGzip parses the gzip-compressed INI file at path, which must end
+// in ".ini.gz". As with ParseFile, relative includes and file references
+// are resolved against the directory of path.
+func (p *Parser) ParseFileGzip(path string) error {
+	if err := checkGzipPath(path); err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	if err := p.parseFrom(gr, filepath.Dir(path), p.initialSection(), false); err != nil {
+		return err
+	}
+	p.dirty = false
+	return nil
+}
+
+// SaveToFileGzip writes the serialized parser gzip-compressed to the file at
+// path, which must end in ".ini.gz".
+func (p *Parser) SaveToFileGzip(path string) error {
+	if err := checkGzipPath(path); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	if _, err := p.WriteTo(gw); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	p.dirty = false
+	return nil
+}
@@ -0,0 +1,56 @@
+package iniparser
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestGzipRoundTrip(t *testing.T) {
+	p := loadSample(t)
+	path := filepath.Join(t.TempDir(), "config.ini.gz")
+
+	if err := p.SaveToFileGzip(path); err != nil {
+		t.Fatalf("SaveToFileGzip: %v", err)
+	}
+
+	loaded := NewParser()
+	if err := loaded.ParseFileGzip(path); err != nil {
+		t.Fatalf("ParseFileGzip: %v", err)
+	}
+	if got, want := loaded.ToString(), p.ToString(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGzipRequiresSuffix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.gz")
+
+	if err := loadSample(t).SaveToFileGzip(path); !errors.Is(err, ErrNotGzipINI) {
+		t.Errorf("SaveToFileGzip: got %v, want %v", err, ErrNotGzipINI)
+	}
+	if err := NewParser().ParseFileGzip(path); !errors.Is(err, ErrNotGzipINI) {
+		t.Errorf("ParseFileGzip: got %v, want %v", err, ErrNotGzipINI)
+	}
+}
+
+func TestParseFileGzipFileReference(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"secret.txt": "hunter2\n"})
+	src := NewParser()
+	if err := src.Set("db", "password", "@file:secret.txt"); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.ini.gz")
+	if err := src.SaveToFileGzip(path); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewParser()
+	p.SetFileReferencePrefix("@file:")
+	if err := p.ParseFileGzip(path); err != nil {
+		t.Fatalf("ParseFileGzip: %v", err)
+	}
+	if got, _ := p.Get("db", "password"); got != "hunter2" {
+		t.Errorf("password = %q, want %q", got, "hunter2")
+	}
+}
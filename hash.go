@@ -0,0 +1,37 @@
+package iniparser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Hash returns the hex-encoded SHA-256 digest of the parser content. Global
+// keys, sections and keys are hashed in sorted order, so parsers holding the
+// same data hash identically whatever order it was loaded in. Empty sections
+// count as content.
+func (p *Parser) Hash() string {
+	h := sha256.New()
+
+	writeHashKeys(h, p.globalKeys)
+
+	sections := p.GetSectionNames()
+	sort.Strings(sections)
+	for _, section := range sections {
+		// Length prefixes keep the encoding unambiguous whatever bytes
+		// the names and values contain.
+		fmt.Fprintf(h, "S%d:%s", len(section), section)
+		writeHashKeys(h, p.data[section])
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writeHashKeys(w io.Writer, keys map[string]string) {
+	for _, key := range sortedKeys(keys) {
+		value := keys[key]
+		fmt.Fprintf(w, "K%d:%sV%d:%s", len(key), key, len(value), value)
+	}
+}
@@ -0,0 +1,34 @@
+package iniparser
+
+import "testing"
+
+func TestHash(t *testing.T) {
+	first := NewParser()
+	if err := first.LoadFromString("b = 2\na = 1\n[x]\nk1 = v1\nk2 = v2\n[y]\nk = v\n"); err != nil {
+		t.Fatal(err)
+	}
+	second := NewParser()
+	if err := second.LoadFromString("a = 1\nb = 2\n[y]\nk = v\n[x]\nk2 = v2\nk1 = v1\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if first.Hash() != second.Hash() {
+		t.Error("reordered but equal parsers hash differently")
+	}
+	if len(first.Hash()) != 64 {
+		t.Errorf("hash %q is not hex SHA-256", first.Hash())
+	}
+
+	if err := second.Set("x", "k1", "changed"); err != nil {
+		t.Fatal(err)
+	}
+	if first.Hash() == second.Hash() {
+		t.Error("parsers with different values hash identically")
+	}
+
+	moved := NewParser().With("a", "bc", "d")
+	shifted := NewParser().With("a", "b", "cd")
+	if moved.Hash() == shifted.Hash() {
+		t.Error("different key/value split hashes identically")
+	}
+}
@@ -0,0 +1,77 @@
+package iniparser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// includeDirective starts a line that includes another file.
+	includeDirective = "!include "
+	// maxIncludeDepth bounds nested includes, which also stops cycles.
+	maxIncludeDepth = 10
+)
+
+// ErrInvalidInclude is returned for an include directive that cannot be
+// followed.
+var ErrInvalidInclude = errors.New("invalid include")
+
+// SetAllowIncludes enables the "!include path" directive, which parses the
+// file at path in place of the directive line. Relative paths are resolved
+// against the directory of the including file, or the working directory when
+// parsing from a string or reader. Includes are disabled by default so that
+// untrusted input cannot read other files.
+func (p *Parser) SetAllowIncludes(allow bool) {
+	p.allowIncludes = allow
+}
+
+// SetIncludeInheritsSection controls the section that keys at the top of an
+// included file belong to. By default an included file starts outside of any
+// section, like a file parsed on its own, so its leading keys are globals.
+// When inherit is true it starts in the section that was current at the
+// include directive, so its leading keys merge into that section. Either way
+// the including file continues in its own section after the directive.
+func (p *Parser) SetIncludeInheritsSection(inherit bool) {
+	p.includeInherits = inherit
+}
+
+// includePath returns the path named by line if it is an include directive.
+func (p *Parser) includePath(line string) (string, bool) {
+	if !p.allowIncludes || !strings.HasPrefix(line, includeDirective) {
+		return "", false
+	}
+	return strings.TrimSpace(line[len(includeDirective):]), true
+}
+
+func (p *Parser) include(dir, path, section string, inHeader bool) error {
+	if path == "" {
+		return fmt.Errorf("%w: missing path", ErrInvalidInclude)
+	}
+	if p.includeDepth >= maxIncludeDepth {
+		return fmt.Errorf("%w: %s: nested more than %d levels", ErrInvalidInclude, path, maxIncludeDepth)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInclude, err)
+	}
+	defer f.Close()
+
+	if !p.includeInherits {
+		section, inHeader = p.initialSection(), false
+	}
+
+	p.includeDepth++
+	defer func() { p.includeDepth-- }()
+
+	if err := p.parseFrom(f, filepath.Dir(path), section, inHeader); err != nil {
+		return fmt.Errorf("include %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package iniparser
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestInclude(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"main.ini":  "[server]\nhost = localhost\n!include extra.ini\nport = 80\n",
+		"extra.ini": "timeout = 5s\n[cache]\nsize = 64\n",
+	})
+
+	t.Run("without inheritance", func(t *testing.T) {
+		p := NewParser()
+		p.SetAllowIncludes(true)
+		if err := p.ParseFile(filepath.Join(dir, "main.ini")); err != nil {
+			t.Fatalf("ParseFile: %v", err)
+		}
+
+		want := map[string]map[string]string{
+			"server": {"host": "localhost", "port": "80"},
+			"cache":  {"size": "64"},
+		}
+		if got := p.GetSections(); !reflect.DeepEqual(got, want) {
+			t.Errorf("sections = %v, want %v", got, want)
+		}
+		if got := p.GetGlobalKeys(); !reflect.DeepEqual(got, map[string]string{"timeout": "5s"}) {
+			t.Errorf("globals = %v", got)
+		}
+	})
+
+	t.Run("with inheritance", func(t *testing.T) {
+		p := NewParser()
+		p.SetAllowIncludes(true)
+		p.SetIncludeInheritsSection(true)
+		if err := p.ParseFile(filepath.Join(dir, "main.ini")); err != nil {
+			t.Fatalf("ParseFile: %v", err)
+		}
+
+		want := map[string]map[string]string{
+			"server": {"host": "localhost", "timeout": "5s", "port": "80"},
+			"cache":  {"size": "64"},
+		}
+		if got := p.GetSections(); !reflect.DeepEqual(got, want) {
+			t.Errorf("sections = %v, want %v", got, want)
+		}
+		if p.HasGlobals() {
+			t.Errorf("globals = %v, want none", p.GetGlobalKeys())
+		}
+	})
+}
+
+func TestIncludeErrors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"missing.ini": "!include nope.ini\n",
+		"loop.ini":    "!include loop.ini\n",
+	})
+
+	for _, name := range []string{"missing.ini", "loop.ini"} {
+		p := NewParser()
+		p.SetAllowIncludes(true)
+		if err := p.ParseFile(filepath.Join(dir, name)); !errors.Is(err, ErrInvalidInclude) {
+			t.Errorf("%s: got %v, want %v", name, err, ErrInvalidInclude)
+		}
+	}
+
+	if err := NewParser().ParseFile(filepath.Join(dir, "missing.ini")); !errors.Is(err, ErrMissingDelimiter) {
+		t.Errorf("includes disabled: got %v, want %v", err, ErrMissingDelimiter)
+	}
+}
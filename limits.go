@@ -0,0 +1,57 @@
+package iniparser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrLimitExceeded is returned when parsing goes beyond the limits set with
+// SetLimits.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// SetLimits bounds the number of sections and the number of keys per section
+// (global keys included) that parsing accepts, so that a huge configuration
+// from an untrusted source fails with ErrLimitExceeded instead of exhausting
+// memory. Exceeding a limit stops parsing even when SetSkipErrors is
+// enabled. Zero means unlimited. The limits do not apply to values set
+// programmatically.
+func (p *Parser) SetLimits(maxSections, maxKeysPerSection int) {
+	p.maxSections, p.maxKeys = maxSections, maxKeysPerSection
+}
+
+// SetMaxValueLength bounds the length in bytes of the values that parsing
+// accepts, as written in the source. A longer value fails with
+// ErrLimitExceeded and, like the limits of SetLimits, stops parsing even
+// when SetSkipErrors is enabled. Zero means unlimited.
+func (p *Parser) SetMaxValueLength(n int) {
+	p.maxValueLen = n
+}
+
+func (p *Parser) checkValueLength(key, value string) error {
+	if p.maxValueLen > 0 && len(value) > p.maxValueLen {
+		return fmt.Errorf("%w: value of key %q is %d bytes, more than %d", ErrLimitExceeded, key, len(value), p.maxValueLen)
+	}
+	return nil
+}
+
+func (p *Parser) checkSectionLimit(section string) error {
+	if p.maxSections > 0 && !p.HasSection(section) && len(p.sections) >= p.maxSections {
+		return fmt.Errorf("%w: more than %d sections", ErrLimitExceeded, p.maxSections)
+	}
+	return nil
+}
+
+func (p *Parser) checkKeyLimit(section, key string) error {
+	resolved := p.resolveSection(section)
+	if resolved != "" {
+		if err := p.checkSectionLimit(resolved); err != nil {
+			return err
+		}
+	}
+
+	keys, _ := p.sectionKeys(resolved)
+	if _, ok := keys[key]; p.maxKeys > 0 && !ok && len(keys) >= p.maxKeys {
+		return fmt.Errorf("%w: more than %d keys in section %q", ErrLimitExceeded, p.maxKeys, resolved)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package iniparser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSetLimits(t *testing.T) {
+	tests := []struct {
+		name                string
+		maxSections, maxKey int
+		input               string
+		err                 error
+	}{
+		{"within limits", 2, 2, "g = 1\n[a]\nk1 = v\nk2 = v\n[b]\nk = v\n[a]\nk1 = w\n", nil},
+		{"too many sections", 2, 0, "[a]\n[b]\n[c]\n", ErrLimitExceeded},
+		{"too many keys", 0, 2, "[a]\nk1 = v\nk2 = v\nk3 = v\n", ErrLimitExceeded},
+		{"too many globals", 0, 1, "g1 = v\ng2 = v\n", ErrLimitExceeded},
+		{"unlimited", 0, 0, "[a]\n[b]\n[c]\nk1 = v\nk2 = v\nk3 = v\n", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser()
+			p.SetLimits(tt.maxSections, tt.maxKey)
+			err := p.LoadFromString(tt.input)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("got %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSetLimitsIgnoresSkipErrors(t *testing.T) {
+	p := NewParser()
+	p.SetLimits(1, 0)
+	p.SetSkipErrors(true)
+
+	err := p.LoadFromString("[a]\nk = v\n[b]\nk = v\n")
+	if !errors.Is(err, ErrLimitExceeded) || !strings.HasPrefix(err.Error(), "line 3:") {
+		t.Errorf("got %v, want a line 3 %v error", err, ErrLimitExceeded)
+	}
+}
+
+func TestSetMaxValueLength(t *testing.T) {
+	p := NewParser()
+	p.SetMaxValueLength(5)
+	if err := p.LoadFromString("[s]\nshort = abcde\n"); err != nil {
+		t.Fatalf("value within the limit: %v", err)
+	}
+
+	p = NewParser()
+	p.SetMaxValueLength(5)
+	p.SetSkipErrors(true)
+	err := p.LoadFromString("[s]\nshort = abc\nlong = abcdef\n")
+	if !errors.Is(err, ErrLimitExceeded) || !strings.HasPrefix(err.Error(), "line 3:") {
+		t.Errorf("got %v, want a line 3 %v error", err, ErrLimitExceeded)
+	}
+
+	p = NewParser()
+	if err := p.LoadFromString("[s]\nlong = " + strings.Repeat("x", 1000) + "\n"); err != nil {
+		t.Errorf("no limit: %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package iniparser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Warning describes a suspicious but valid construct found while parsing.
+type Warning struct {
+	Line         int
+	Section, Key string
+	Message      string
+}
+
+// String formats the warning as "line N: [section] key: message", leaving
+// out the key for warnings about a whole section.
+func (w Warning) String() string {
+	if w.Key == "" {
+		return fmt.Sprintf("line %d: [%s]: %s", w.Line, w.Section, w.Message)
+	}
+	return fmt.Sprintf("line %d: [%s] %s: %s", w.Line, w.Section, w.Key, w.Message)
+}
+
+// Lint returns the warnings collected while parsing, in the order they were
+// found.
+func (p *Parser) Lint() []Warning {
+	warnings := make([]Warning, len(p.warnings))
+	copy(warnings, p.warnings)
+	return warnings
+}
+
+// SetWarningWriter makes parsing write each warning reported by Lint, such
+// as trailing whitespace, a section without keys or an empty [] header, and
+// each collision reported by Conflicts, such as a duplicate key, to w as a
+// line of text as soon as it is found. Write errors are ignored. A nil w
+// disables it.
+func (p *Parser) SetWarningWriter(w io.Writer) {
+	p.warningWriter = w
+}
+
+func (p *Parser) warn(line int, section, key, message string) {
+	w := Warning{Line: line, Section: section, Key: key, Message: message}
+	p.warnings = append(p.warnings, w)
+	p.writeWarning(w.String())
+}
+
+func (p *Parser) writeWarning(s string) {
+	if p.warningWriter != nil {
+		fmt.Fprintln(p.warningWriter, s)
+	}
+}
+
+// lintValue records warnings about a parsed value.
+func (p *Parser) lintValue(line int, section, key, value string) {
+	if strings.HasPrefix(value, p.keyDelimiter(section)) {
+		p.warn(line, section, key, fmt.Sprintf("value %q starts with the delimiter, possibly a typo", value))
+	}
+}
+
+// lintSpacing warns about trailing whitespace on the line of a key, unless
+// it is a whitespace-only value kept by SetPreserveSpaceValues.
+func (p *Parser) lintSpacing(line int, section, key, raw, rawValue string) {
+	if strings.TrimRight(raw, " \t") != raw && strings.TrimSpace(rawValue) != "" {
+		p.warn(line, section, key, "trailing whitespace")
+	}
+}
+
+// warnEmptySection warns about the section whose header at line was not
+// followed by any key, unless the section got keys elsewhere, as with a
+// repeated header. A zero line means there is nothing to report.
+func (p *Parser) warnEmptySection(line int, section string) {
+	if line > 0 && len(p.data[section]) == 0 {
+		p.warn(line, section, "", "section has no keys")
+	}
+}
+
+// Conflicts returns descriptions of the collisions resolved silently while
+// parsing, in the order they were found: a key set again, whose last value
+// wins, and a section header repeated, whose keys are merged into the
+// earlier section. Only repeats within the same file are reported; a later
+// load overriding earlier values is not a conflict. Repeated keys are not
+// conflicts while SetMultiKey is enabled.
+func (p *Parser) Conflicts() []string {
+	conflicts := make([]string, len(p.conflicts))
+	copy(conflicts, p.conflicts)
+	return conflicts
+}
+
+func (p *Parser) conflict(line int, format string, args ...any) {
+	c := fmt.Sprintf("line %d: ", line) + fmt.Sprintf(format, args...)
+	p.conflicts = append(p.conflicts, c)
+	p.writeWarning(c)
+}
+
+// checkDuplicateKey records a conflict if key was already set in section
+// earlier in the same file, then records line in seen, which maps resolved
+// sections to the lines of their keys.
+func (p *Parser) checkDuplicateKey(seen map[string]map[string]int, line int, section, key string) {
+	resolved := p.resolveSection(section)
+	previous, ok := seen[resolved][key]
+	if seen[resolved] == nil {
+		seen[resolved] = make(map[string]int)
+	}
+	seen[resolved][key] = line
+	if p.multiKey || !ok {
+		return
+	}
+	if block, ok := p.currentBlock(section); ok {
+		// Only a key repeated within one block of a repeatable section
+		// is a conflict.
+		if _, ok := block[key]; !ok {
+			return
+		}
+	}
+	p.conflict(line, "duplicate key %s overwrites the value from line %d", qualifiedKey(resolved, key), previous)
+}
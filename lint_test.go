@@ -0,0 +1,143 @@
+package iniparser
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLintDoubledDelimiter(t *testing.T) {
+	t.Run("doubled delimiter", func(t *testing.T) {
+		p := NewParser()
+		if err := p.LoadFromString("[s]\nkey==value\n"); err != nil {
+			t.Fatal(err)
+		}
+
+		want := []Warning{{
+			Line:    2,
+			Section: "s",
+			Key:     "key",
+			Message: `value "=value" starts with the delimiter, possibly a typo`,
+		}}
+		if got := p.Lint(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("single delimiter", func(t *testing.T) {
+		p := NewParser()
+		if err := p.LoadFromString("[s]\nkey=value\n"); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := p.Lint(); len(got) != 0 {
+			t.Errorf("got %v, want no warnings", got)
+		}
+	})
+}
+
+func TestConflicts(t *testing.T) {
+	p := NewParser()
+	err := p.LoadFromString(`name = a
+name = b
+[server]
+host = localhost
+[database]
+user = admin
+[server]
+host = example.com
+port = 8080
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"line 2: duplicate key name overwrites the value from line 1",
+		"line 7: duplicate section [server] merged with the earlier one",
+		"line 8: duplicate key server.host overwrites the value from line 4",
+	}
+	if got := p.Conflicts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Conflicts() = %q, want %q", got, want)
+	}
+
+	p = NewParser()
+	p.SetMultiKey(true)
+	if err := p.LoadFromString("[s]\nk = 1\nk = 2\n"); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Conflicts(); len(got) != 0 {
+		t.Errorf("Conflicts() with SetMultiKey = %q, want none", got)
+	}
+}
+
+func TestConflictsAcrossLoads(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadDefaults(strings.NewReader(sampleINI)); err != nil {
+		t.Fatalf("LoadDefaults: %v", err)
+	}
+	if err := p.LoadFromString("name = custom\n[server]\nport = 9090\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := p.Conflicts(); len(got) != 0 {
+		t.Errorf("Conflicts() = %q, want none", got)
+	}
+}
+
+func TestSetWarningWriter(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewParser()
+	p.SetWarningWriter(&buf)
+	if err := p.LoadFromString("[server]\nhost = a\nhost = b\nport == 80\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "line 3: duplicate key server.host overwrites the value from line 2\n" +
+		`line 4: [server] port: value "= 80" starts with the delimiter, possibly a typo` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	p = NewParser()
+	p.SetWarningWriter(&buf)
+	p.SetWarningWriter(nil)
+	if err := p.LoadFromString("a = 1\na = 2\n"); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("nil writer: got %q, want nothing written", buf.String())
+	}
+}
+
+func TestWarningsForSpacingAndEmptySections(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewParser()
+	p.SetAllowEmptySectionName(true)
+	p.SetWarningWriter(&buf)
+	err := p.LoadFromString("[empty]\n[server]\nhost = localhost  \n[]\nname = demo\n[last]\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "line 1: [empty]: section has no keys\n" +
+		"line 3: [server] host: trailing whitespace\n" +
+		"line 4: []: empty section header, the keys below it are global\n" +
+		"line 6: [last]: section has no keys\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := len(p.Lint()); got != 4 {
+		t.Errorf("Lint() has %d warnings, want 4", got)
+	}
+
+	p = NewParser()
+	if err := p.LoadFromString("[a]\nk = v\n[a]\n"); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Lint(); len(got) != 0 {
+		t.Errorf("repeated header: Lint() = %v, want no warnings", got)
+	}
+}
@@ -0,0 +1,106 @@
+package iniparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// qualifiedKey joins section and key as "section.key", or returns key alone
+// for a global key.
+func qualifiedKey(section, key string) string {
+	if section == "" {
+		return key
+	}
+	return section + "." + key
+}
+
+// splitQualifiedKey splits path on its last dot into a section and a key.
+// A path without a dot names a global key.
+func splitQualifiedKey(path string) (string, string) {
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[:i], path[i+1:]
+	}
+	return "", path
+}
+
+// PatchExisting overwrites the keys of p that also exist in other with the
+// values from other. Keys and sections that p does not already have are not
+// added; they are returned as "section.key" (or "key" for globals) in the
+// order of other.Entries.
+func (p *Parser) PatchExisting(other *Parser) []string {
+	var ignored []string
+	for _, e := range other.Entries() {
+		keys, ok := p.sectionKeys(e.Section)
+		if !ok {
+			ignored = append(ignored, qualifiedKey(e.Section, e.Key))
+			continue
+		}
+		if _, ok := keys[e.Key]; !ok {
+			ignored = append(ignored, qualifiedKey(e.Section, e.Key))
+			continue
+		}
+		p.setValue(e.Section, e.Key, e.Value)
+		p.dirty = true
+	}
+	return ignored
+}
+
+// SetUnsetSentinel sets a value that deletes a key when it appears in the
+// overlay passed to Merge, so override files can remove keys: with the
+// sentinel "@unset", an overlay line "key = @unset" removes key instead of
+// setting it to "@unset". An empty sentinel, the default, disables this.
+func (p *Parser) SetUnsetSentinel(sentinel string) {
+	p.unsetSentinel = sentinel
+}
+
+// Merge copies every section and key of other into p, overwriting values
+// that already exist. Sections missing from p are created in the order they
+// appear in other. Values equal to the sentinel set by SetUnsetSentinel
+// delete the key from p instead. In flat mode it fails with
+// ErrUnexpectedSection, without merging anything, if other has sections.
+func (p *Parser) Merge(other *Parser) error {
+	if p.flat {
+		for _, section := range other.sections {
+			if section != "" {
+				return fmt.Errorf("%w: %s", ErrUnexpectedSection, section)
+			}
+		}
+	}
+
+	for _, section := range other.sections {
+		p.createSectionIfNotExist(section)
+	}
+	for _, e := range other.Entries() {
+		if p.unsetSentinel != "" && e.Value == p.unsetSentinel {
+			p.deleteKey(e.Section, e.Key)
+			continue
+		}
+		p.setValue(e.Section, e.Key, e.Value)
+	}
+	p.dirty = true
+	return nil
+}
+
+// MergeSection copies the keys of a single section of other into p like
+// Merge, creating the section if needed. It fails with ErrSectionNotFound if
+// other has no such section, and with ErrUnexpectedSection in flat mode.
+func (p *Parser) MergeSection(other *Parser, section string) error {
+	if p.flat && section != "" {
+		return fmt.Errorf("%w: %s", ErrUnexpectedSection, section)
+	}
+	if !other.HasSection(section) {
+		return fmt.Errorf("%w: %s", ErrSectionNotFound, section)
+	}
+
+	p.createSectionIfNotExist(section)
+	for _, key := range other.keyOrder[section] {
+		value := other.data[section][key]
+		if p.unsetSentinel != "" && value == p.unsetSentinel {
+			p.deleteKey(section, key)
+			continue
+		}
+		p.setValue(section, key, value)
+	}
+	p.dirty = true
+	return nil
+}
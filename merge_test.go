@@ -0,0 +1,128 @@
+package iniparser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPatchExisting(t *testing.T) {
+	p := loadSample(t)
+
+	overlay := NewParser()
+	err := overlay.LoadFromString("name = patched\nextra = 1\n[server]\nport = 9090\ntimeout = 5s\n[cache]\nsize = 64\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ignored := p.PatchExisting(overlay)
+
+	wantIgnored := []string{"extra", "server.timeout", "cache.size"}
+	if !reflect.DeepEqual(ignored, wantIgnored) {
+		t.Errorf("ignored = %v, want %v", ignored, wantIgnored)
+	}
+	if got, _ := p.Get("server", "port"); got != "9090" {
+		t.Errorf("port = %q, want %q", got, "9090")
+	}
+	if got, _ := p.Get("", "name"); got != "patched" {
+		t.Errorf("name = %q, want %q", got, "patched")
+	}
+	if p.HasKey("server", "timeout") || p.HasSection("cache") {
+		t.Error("overlay introduced new keys or sections")
+	}
+}
+
+func TestPatchExistingMultiKey(t *testing.T) {
+	p := NewParser()
+	p.SetMultiKey(true)
+	p.SetUnquoteValues(true)
+	if err := p.LoadFromString("[s]\nm = 1\nm = \"2\"\n"); err != nil {
+		t.Fatal(err)
+	}
+	overlay := NewParser()
+	if err := overlay.LoadFromString("[s]\nm = 3\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	p.PatchExisting(overlay)
+
+	if got, ok := p.GetAll("s", "m"); !ok || !reflect.DeepEqual(got, []string{"3"}) {
+		t.Errorf("GetAll = (%q, %v), want [3]", got, ok)
+	}
+	if got, err := p.GetSingle("s", "m"); err != nil || got != "3" {
+		t.Errorf("GetSingle = (%q, %v), want 3", got, err)
+	}
+	if got, _ := p.GetRaw("s", "m"); got != "3" {
+		t.Errorf("GetRaw = %q, want 3", got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	p := loadSample(t)
+	p.SetUnsetSentinel("@unset")
+
+	overlay := NewParser()
+	err := overlay.LoadFromString("name = @unset\n[server]\nport = 9090\nhost = @unset\n[cache]\nsize = 64\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Merge(overlay); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	want := map[string]map[string]string{
+		"server":   {"port": "9090"},
+		"database": {"user": "admin", "password": "secret"},
+		"cache":    {"size": "64"},
+	}
+	if got := p.GetSections(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if p.HasKey("", "name") {
+		t.Error("global key was not unset")
+	}
+	if want := []string{"server", "database", "cache"}; !reflect.DeepEqual(p.GetSectionNames(), want) {
+		t.Errorf("section order = %v, want %v", p.GetSectionNames(), want)
+	}
+}
+
+func TestMergeWithoutSentinel(t *testing.T) {
+	p := loadSample(t)
+
+	overlay := NewParser()
+	if err := overlay.LoadFromString("[server]\nhost = @unset\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Merge(overlay); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if got, _ := p.Get("server", "host"); got != "@unset" {
+		t.Errorf("got %q, want the literal %q", got, "@unset")
+	}
+}
+
+func TestMergeSection(t *testing.T) {
+	p := loadSample(t)
+
+	other := NewParser()
+	if err := other.LoadFromString("[server]\nport = 9090\ntimeout = 5s\n[cache]\nsize = 64\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.MergeSection(other, "server"); err != nil {
+		t.Fatalf("MergeSection: %v", err)
+	}
+	want := map[string]string{"host": "localhost", "port": "9090", "timeout": "5s"}
+	if got := p.GetSections()["server"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if p.HasSection("cache") {
+		t.Error("unrelated section was merged")
+	}
+
+	if err := p.MergeSection(other, "auth"); !errors.Is(err, ErrSectionNotFound) {
+		t.Errorf("missing section: got %v, want %v", err, ErrSectionNotFound)
+	}
+}
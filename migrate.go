@@ -0,0 +1,49 @@
+package iniparser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// migrationsSection records the migrations applied by Migrate.
+const migrationsSection = "_migrations"
+
+// ErrMigrationFailed is returned by Migrate when a migration fails.
+var ErrMigrationFailed = errors.New("migration failed")
+
+// Migration is a named change to the content of a parser, such as renaming
+// a key after a schema change.
+type Migration struct {
+	Name string
+	Run  func(*Parser) error
+}
+
+// Migrate runs, in order, the migrations not yet recorded in the
+// [_migrations] section, and records each one there as "name = applied"
+// once it succeeds, so that saving and migrating again skips it. When a
+// migration fails, its partial changes are undone and Migrate stops with an
+// ErrMigrationFailed error naming it; migrations that ran before it stay
+// applied and recorded.
+func (p *Parser) Migrate(migrations []Migration) error {
+	for _, m := range migrations {
+		if m.Name == "" {
+			return fmt.Errorf("%w: %v", ErrMigrationFailed, ErrEmptyKey)
+		}
+		if p.HasKey(migrationsSection, m.Name) {
+			continue
+		}
+
+		token := p.Snapshot()
+		err := m.Run(p)
+		if err == nil {
+			err = p.Set(migrationsSection, m.Name, "applied")
+		}
+		if err != nil {
+			_ = p.Rollback(token)
+			delete(p.snapshots, token)
+			return fmt.Errorf("%w: %s: %w", ErrMigrationFailed, m.Name, err)
+		}
+		delete(p.snapshots, token)
+	}
+	return nil
+}
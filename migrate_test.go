@@ -0,0 +1,59 @@
+package iniparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	p := loadSample(t)
+	runs := map[string]int{}
+	migrations := []Migration{
+		{Name: "rename-host", Run: func(p *Parser) error {
+			runs["rename-host"]++
+			host, _ := p.Get("server", "host")
+			return p.Set("server", "address", host)
+		}},
+		{Name: "add-cache", Run: func(p *Parser) error {
+			runs["add-cache"]++
+			return p.Set("cache", "size", "64")
+		}},
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := p.Migrate(migrations); err != nil {
+			t.Fatalf("Migrate run %d: %v", i+1, err)
+		}
+	}
+
+	if runs["rename-host"] != 1 || runs["add-cache"] != 1 {
+		t.Errorf("runs = %v, want each migration run once", runs)
+	}
+	if got, _ := p.Get("server", "address"); got != "localhost" {
+		t.Errorf("server.address = %q, want %q", got, "localhost")
+	}
+	for _, name := range []string{"rename-host", "add-cache"} {
+		if !p.HasKey("_migrations", name) {
+			t.Errorf("migration %q not recorded", name)
+		}
+	}
+}
+
+func TestMigrateFailure(t *testing.T) {
+	p := loadSample(t)
+	errBroken := errors.New("broken")
+	err := p.Migrate([]Migration{{Name: "broken", Run: func(p *Parser) error {
+		_ = p.Set("server", "port", "1")
+		return errBroken
+	}}})
+
+	if !errors.Is(err, ErrMigrationFailed) || !errors.Is(err, errBroken) {
+		t.Fatalf("got %v, want an error wrapping %v and %v", err, ErrMigrationFailed, errBroken)
+	}
+	if got, _ := p.Get("server", "port"); got != "8080" {
+		t.Errorf("failed migration was not undone: port = %q", got)
+	}
+	if p.HasKey("_migrations", "broken") {
+		t.Error("failed migration was recorded")
+	}
+}
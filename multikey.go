@@ -0,0 +1,53 @@
+package iniparser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrAmbiguousValue is returned by GetSingle for a key holding several
+// values.
+var ErrAmbiguousValue = errors.New("key has multiple values")
+
+// SetMultiKey makes parsing keep every value of a key repeated within a
+// section, as used for lists such as several "server = ..." lines. Get still
+// returns the last value, GetAll returns all of them and GetSingle reports
+// the ambiguity. Setting a key with Set replaces all its values.
+func (p *Parser) SetMultiKey(enable bool) {
+	p.multiKey = enable
+}
+
+func (p *Parser) storeValues(section, key string, values []string) {
+	section = p.resolveSection(section)
+	if p.multiValues[section] == nil {
+		p.multiValues[section] = make(map[string][]string)
+	}
+	p.multiValues[section][key] = values
+}
+
+// GetAll returns every value of key in section in the order they were
+// parsed. Keys that were not repeated yield a single value.
+func (p *Parser) GetAll(section, key string) ([]string, bool) {
+	if values, ok := p.multiValues[p.resolveSection(section)][key]; ok {
+		return append([]string(nil), values...), true
+	}
+	value, ok := p.Get(section, key)
+	if !ok {
+		return nil, false
+	}
+	return []string{value}, true
+}
+
+// GetSingle returns the value of key in section, failing with
+// ErrAmbiguousValue if multi-key parsing collected several values for it
+// rather than silently picking one, or with ErrKeyNotFound if it is missing.
+func (p *Parser) GetSingle(section, key string) (string, error) {
+	values, ok := p.GetAll(section, key)
+	if !ok {
+		return "", fmt.Errorf("%w: [%s] %s", ErrKeyNotFound, section, key)
+	}
+	if len(values) > 1 {
+		return "", fmt.Errorf("%w: [%s] %s has %d values", ErrAmbiguousValue, section, key, len(values))
+	}
+	return values[0], nil
+}
@@ -0,0 +1,50 @@
+package iniparser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMultiKey(t *testing.T) {
+	p := NewParser()
+	p.SetMultiKey(true)
+	if err := p.LoadFromString("[pool]\nserver = a\nserver = b\nsize = 2\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := p.GetAll("pool", "server"); !ok || !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("GetAll(server) = (%v, %v)", got, ok)
+	}
+	if got, _ := p.Get("pool", "server"); got != "b" {
+		t.Errorf("Get(server) = %q, want the last value %q", got, "b")
+	}
+
+	if got, err := p.GetSingle("pool", "size"); err != nil || got != "2" {
+		t.Errorf("GetSingle(size) = (%q, %v), want (%q, nil)", got, err, "2")
+	}
+	if _, err := p.GetSingle("pool", "server"); !errors.Is(err, ErrAmbiguousValue) {
+		t.Errorf("GetSingle(server): got %v, want %v", err, ErrAmbiguousValue)
+	}
+	if _, err := p.GetSingle("pool", "missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("GetSingle(missing): got %v, want %v", err, ErrKeyNotFound)
+	}
+
+	if err := p.Set("pool", "server", "c"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := p.GetSingle("pool", "server"); err != nil || got != "c" {
+		t.Errorf("after Set: GetSingle(server) = (%q, %v), want (%q, nil)", got, err, "c")
+	}
+}
+
+func TestMultiKeyDisabled(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("[pool]\nserver = a\nserver = b\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := p.GetSingle("pool", "server"); err != nil || got != "b" {
+		t.Errorf("GetSingle(server) = (%q, %v), want (%q, nil)", got, err, "b")
+	}
+}
@@ -0,0 +1,107 @@
+package iniparser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidOverride is returned by ApplyOverrides for a malformed entry.
+var ErrInvalidOverride = errors.New("invalid override")
+
+// ApplyOverrides sets values from command-line style entries of the form
+// "section.key=value", or "key=value" for a global key. The path is split on
+// its last dot, so section names may themselves contain dots. Missing
+// sections are created. Entries are all checked, with the rules of Set,
+// before any is applied, so a malformed entry leaves the parser unchanged.
+func (p *Parser) ApplyOverrides(overrides []string) error {
+	type override struct{ section, key, value string }
+	parsed := make([]override, 0, len(overrides))
+
+	for _, entry := range overrides {
+		path, value, found := strings.Cut(entry, "=")
+		if !found {
+			return fmt.Errorf("%w %q: %v", ErrInvalidOverride, entry, ErrMissingDelimiter)
+		}
+
+		section, key := splitQualifiedKey(path)
+		section, key = strings.TrimSpace(section), strings.TrimSpace(key)
+		if err := p.checkSet(section, key); err != nil {
+			return fmt.Errorf("%w %q: %w", ErrInvalidOverride, entry, err)
+		}
+
+		parsed = append(parsed, override{section, key, strings.TrimSpace(value)})
+	}
+
+	for _, o := range parsed {
+		p.setValue(o.section, o.key, o.value)
+		p.dirty = true
+	}
+	return nil
+}
+
+// ErrInvalidPatch is returned by ApplyPatch for a malformed patch line.
+var ErrInvalidPatch = errors.New("invalid patch")
+
+// ApplyPatch applies a patch made of one edit per line: "+section.key=value"
+// sets a key, creating its section if needed, and "-section.key" deletes it.
+// Paths follow ApplyOverrides, so "+key=value" and "-key" edit global keys.
+// Blank lines and comment lines are ignored, and deleting a key that does
+// not exist is not an error. Lines are all checked, with the rules of Set
+// for additions, before any is applied; errors name the offending line, as
+// in "line 3: invalid patch ...".
+func (p *Parser) ApplyPatch(patch string) error {
+	type edit struct {
+		del                 bool
+		section, key, value string
+	}
+	var edits []edit
+
+	for i, line := range strings.Split(patch, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if _, ok := p.commentPrefix(line); ok {
+			continue
+		}
+
+		if line[0] != '+' && line[0] != '-' {
+			return fmt.Errorf("line %d: %w %q: must start with + or -", i+1, ErrInvalidPatch, line)
+		}
+		e := edit{del: line[0] == '-'}
+		path := line[1:]
+		if !e.del {
+			var found bool
+			path, e.value, found = strings.Cut(path, "=")
+			if !found {
+				return fmt.Errorf("line %d: %w %q: %v", i+1, ErrInvalidPatch, line, ErrMissingDelimiter)
+			}
+			e.value = strings.TrimSpace(e.value)
+		}
+
+		e.section, e.key = splitQualifiedKey(path)
+		e.section, e.key = strings.TrimSpace(e.section), strings.TrimSpace(e.key)
+		if e.key == "" {
+			return fmt.Errorf("line %d: %w %q: %v", i+1, ErrInvalidPatch, line, ErrEmptyKey)
+		}
+		if !e.del {
+			if err := p.checkSet(e.section, e.key); err != nil {
+				return fmt.Errorf("line %d: %w %q: %w", i+1, ErrInvalidPatch, line, err)
+			}
+		}
+		edits = append(edits, e)
+	}
+
+	for _, e := range edits {
+		if !e.del {
+			p.setValue(e.section, e.key, e.value)
+			p.dirty = true
+			continue
+		}
+		if p.deleteKey(e.section, e.key) {
+			p.dirty = true
+		}
+	}
+	return nil
+}
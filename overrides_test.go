@@ -0,0 +1,113 @@
+package iniparser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		p := loadSample(t)
+		err := p.ApplyOverrides([]string{"server.port=9090", "name=override", "cache.size=64"})
+		if err != nil {
+			t.Fatalf("ApplyOverrides: %v", err)
+		}
+
+		tests := []struct{ section, key, want string }{
+			{"server", "port", "9090"},
+			{"", "name", "override"},
+			{"cache", "size", "64"},
+		}
+		for _, tt := range tests {
+			if got, _ := p.Get(tt.section, tt.key); got != tt.want {
+				t.Errorf("Get(%q, %q) = %q, want %q", tt.section, tt.key, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		p := loadSample(t)
+		for _, entry := range []string{"server.port", "server.=1"} {
+			err := p.ApplyOverrides([]string{"server.host=example.com", entry})
+			if !errors.Is(err, ErrInvalidOverride) {
+				t.Errorf("ApplyOverrides(%q): got %v, want %v", entry, err, ErrInvalidOverride)
+			}
+		}
+		if got, _ := p.Get("server", "host"); got != "localhost" {
+			t.Errorf("parser modified by a failed override: host = %q", got)
+		}
+	})
+}
+
+func TestApplyPatch(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		p := loadSample(t)
+		err := p.ApplyPatch(`# rotate credentials
++cache.size=64
++server.port = 9090
+
+-database.password
+-database.missing
+`)
+		if err != nil {
+			t.Fatalf("ApplyPatch: %v", err)
+		}
+
+		if got, _ := p.Get("cache", "size"); got != "64" {
+			t.Errorf("cache.size = %q, want %q", got, "64")
+		}
+		if got, _ := p.Get("server", "port"); got != "9090" {
+			t.Errorf("server.port = %q, want %q", got, "9090")
+		}
+		if p.HasKey("database", "password") {
+			t.Error("database.password still present")
+		}
+		if !p.IsDirty() {
+			t.Error("IsDirty() = false after patching")
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		tests := []struct{ patch, want string }{
+			{"+server.port=1\nserver.host=x", "line 2: "},
+			{"+server.port", "line 1: "},
+			{"+server.port=1\n\n-server.", "line 3: "},
+		}
+		for _, tt := range tests {
+			p := loadSample(t)
+			err := p.ApplyPatch(tt.patch)
+			if !errors.Is(err, ErrInvalidPatch) || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("ApplyPatch(%q) = %v, want %v at %q", tt.patch, err, ErrInvalidPatch, tt.want)
+			}
+			if got, _ := p.Get("server", "port"); got != "8080" {
+				t.Errorf("parser modified by a failed patch: port = %q", got)
+			}
+		}
+	})
+}
+
+func TestApplyOverridesFlatMode(t *testing.T) {
+	p := NewParser()
+	p.SetFlatMode(true)
+
+	if err := p.ApplyOverrides([]string{"x=1", "s.k=2"}); !errors.Is(err, ErrInvalidOverride) || !errors.Is(err, ErrUnexpectedSection) {
+		t.Errorf("ApplyOverrides: got %v, want %v wrapping %v", err, ErrInvalidOverride, ErrUnexpectedSection)
+	}
+	if p.HasKey("", "x") || p.IsDirty() {
+		t.Error("parser modified by a rejected override")
+	}
+}
+
+func TestApplyPatchFlatMode(t *testing.T) {
+	p := NewParser()
+	p.SetFlatMode(true)
+
+	err := p.ApplyPatch("+x=1\n+s.k=2\n")
+	if !errors.Is(err, ErrInvalidPatch) || !errors.Is(err, ErrUnexpectedSection) || !strings.HasPrefix(err.Error(), "line 2: ") {
+		t.Errorf("got %v, want a line 2 %v wrapping %v", err, ErrInvalidPatch, ErrUnexpectedSection)
+	}
+	if p.HasKey("", "x") || p.IsDirty() {
+		t.Error("parser modified by a rejected patch")
+	}
+}
@@ -0,0 +1,1201 @@
+// Package iniparser implements a small parser for INI configuration files.
+//
+// A file is made of optional global keys followed by sections:
+//
+//	; comment
+//	name = global
+//
+//	[server]
+//	host = localhost
+//	port = 8080
+//
+// Keys that appear before the first section header are stored as globals and
+// are addressed with an empty section name.
+package iniparser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMissingDelimiter is returned when a line is neither a section header
+	// nor a key/value pair.
+	ErrMissingDelimiter = errors.New("missing key/value delimiter")
+	// ErrEmptyKey is returned when a key is empty.
+	ErrEmptyKey = errors.New("empty key")
+	// ErrEmptyValue is returned when a value is empty.
+	ErrEmptyValue = errors.New("empty value")
+	// ErrInvalidName is returned when a key or section name contains a NUL
+	// byte or a line break, which would corrupt the output.
+	ErrInvalidName = errors.New("invalid name")
+	// ErrKeyNotFound is returned when a requested key does not exist.
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrInvalidValue is returned when a value cannot be interpreted as
+	// requested.
+	ErrInvalidValue = errors.New("invalid value")
+	// ErrUnsupportedEncoding is returned for an encoding the parser does not
+	// handle.
+	ErrUnsupportedEncoding = errors.New("unsupported encoding")
+	// ErrUnexpectedSection is returned for sections while SetFlatMode is
+	// enabled.
+	ErrUnexpectedSection = errors.New("section not allowed in flat mode")
+	// ErrTimeout is returned when reading the input takes too long.
+	ErrTimeout = errors.New("timed out reading input")
+	// ErrUnexpectedGlobals is returned when writing a parser that holds
+	// global keys while SetForbidGlobals is enabled.
+	ErrUnexpectedGlobals = errors.New("global keys present in a fully sectioned file")
+	// ErrEmptySectionName is returned for a section header without a name,
+	// such as [], unless SetAllowEmptySectionName is enabled.
+	ErrEmptySectionName = errors.New("empty section name")
+	// ErrInvalidSectionHeader is returned for a line that opens a section
+	// header without closing it, such as [unclosed.
+	ErrInvalidSectionHeader = errors.New("invalid section header")
+)
+
+// Parser holds the sections and keys of a parsed INI file.
+type Parser struct {
+	// sections keeps section names in the order they were first seen.
+	sections []string
+	data     map[string]map[string]string
+	// keyOrder keeps the keys of each section in the order they were
+	// first set.
+	keyOrder   map[string][]string
+	globalKeys map[string]string
+	// globalOrder keeps the global keys in the order they were first set.
+	globalOrder []string
+	// comments holds the comment lines preceding each section header, with
+	// the global leading comments stored under the empty section.
+	comments map[string][]string
+	// keyLines records the source line each key was last parsed from,
+	// with global keys under the empty section.
+	keyLines map[string]map[string]int
+	// multiValues holds every value of keys repeated while SetMultiKey is
+	// enabled, with global keys under the empty section.
+	multiValues map[string]map[string][]string
+	// rawValues holds parsed values as written in the source, before
+	// unquoting and other transformations, with global keys under the empty
+	// section.
+	rawValues map[string]map[string]string
+	// defaults marks the keys whose value was loaded by LoadDefaults, with
+	// global keys under the empty section.
+	defaults        map[string]map[string]bool
+	loadingDefaults bool
+	// defaultSection, when set, receives the keys that would otherwise be
+	// stored as globals.
+	defaultSection string
+	rootSection    string
+	forbidGlobals  bool
+	encodeControl  bool
+	// sectionCutset lists the characters trimmed from section names; empty
+	// means whitespace.
+	sectionCutset string
+	eol           string
+	sectionOpen   string
+	sectionClose  string
+
+	commentPrefixes   []string
+	skipErrors        bool
+	allowEmpty        bool
+	preserveSpace     bool
+	multiKey          bool
+	filePrefix        string
+	maxSections       int
+	maxKeys           int
+	maxValueLen       int
+	unquote           bool
+	transform         func(section, key, value string) string
+	spacing           DelimiterSpacing
+	repeatable        map[string]bool
+	repeated          map[string][]map[string]string
+	dropTrailingEmpty bool
+	toc               bool
+	allowEmptySection bool
+	syntax            map[string]sectionSyntax
+	unsetSentinel     string
+	flat              bool
+
+	allowIncludes   bool
+	includeInherits bool
+	includeDepth    int
+
+	dirty bool
+
+	validators []validator
+	warnings   []Warning
+	conflicts  []string
+	errs       []error
+
+	// warningWriter receives warnings and conflicts as they are found.
+	warningWriter io.Writer
+
+	snapshots    map[int]*snapshot
+	nextSnapshot int
+}
+
+// NewParser returns an empty Parser.
+func NewParser() *Parser {
+	return &Parser{
+		data:       make(map[string]map[string]string),
+		keyOrder:   make(map[string][]string),
+		globalKeys: make(map[string]string),
+		comments:   make(map[string][]string),
+		keyLines:   make(map[string]map[string]int),
+
+		multiValues: make(map[string]map[string][]string),
+		rawValues:   make(map[string]map[string]string),
+	}
+}
+
+// LoadFromString parses the INI content held in s.
+func (p *Parser) LoadFromString(s string) error {
+	return p.parse(strings.NewReader(s))
+}
+
+// ParseBytes parses the INI content held in b without copying it.
+func (p *Parser) ParseBytes(b []byte) error {
+	return p.parse(bytes.NewReader(b))
+}
+
+// LoadFromReader parses the INI content read from r.
+func (p *Parser) LoadFromReader(r io.Reader) error {
+	return p.parse(r)
+}
+
+// LoadFromReaderTimeout parses the INI content read from r, failing with
+// ErrTimeout if reading does not finish within d. The input is read in full
+// by a separate goroutine before parsing, so a timeout leaves the parser
+// unchanged. On timeout r is closed if it implements io.Closer, which
+// unblocks the pending read; the goroutine exits as soon as that read
+// returns.
+func (p *Parser) LoadFromReaderTimeout(r io.Reader, d time.Duration) error {
+	type result struct {
+		content []byte
+		err     error
+	}
+	done := make(chan result, 1)
+
+	go func() {
+		content, err := io.ReadAll(r)
+		done <- result{content, err}
+	}()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return res.err
+		}
+		return p.ParseBytes(res.content)
+	case <-timer.C:
+		if c, ok := r.(io.Closer); ok {
+			c.Close()
+		}
+		return fmt.Errorf("%w after %s", ErrTimeout, d)
+	}
+}
+
+// LoadFromReaders parses readers in sequence as one stream built with
+// io.MultiReader, so later readers override keys set by earlier ones. A line
+// break is inserted between readers, but the current section carries over:
+// keys at the top of a reader belong to the last section of the previous
+// one. Line numbers in errors count from the start of the first reader.
+func (p *Parser) LoadFromReaders(readers ...io.Reader) error {
+	parts := make([]io.Reader, 0, 2*len(readers))
+	for i, r := range readers {
+		if i > 0 {
+			parts = append(parts, strings.NewReader("\n"))
+		}
+		parts = append(parts, r)
+	}
+	return p.parse(io.MultiReader(parts...))
+}
+
+// ParseFile parses the INI file at path.
+func (p *Parser) ParseFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := p.parseFrom(f, filepath.Dir(path), p.initialSection(), false); err != nil {
+		return err
+	}
+	p.dirty = false
+	return nil
+}
+
+// ParseDir parses every file ending in ".ini" in dir, in lexical order, so
+// that later files override keys set by earlier ones as in the usual conf.d
+// layout. Other files and subdirectories are skipped.
+func (p *Parser) ParseDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".ini" {
+			continue
+		}
+		if err := p.ParseFile(filepath.Join(dir, entry.Name())); err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (p *Parser) parse(r io.Reader) error {
+	return p.parseFrom(r, "", p.initialSection(), false)
+}
+
+// parseFrom parses r starting in section, where inHeader reports whether
+// section was opened by a header. Included files are resolved relative to
+// dir.
+func (p *Parser) parseFrom(r io.Reader, dir, section string, inHeader bool) error {
+	r, err := decodeBOM(r)
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	var comments []string
+	// emptyLine is the line of the last section header not yet followed by
+	// a key, or zero.
+	emptyLine, emptySection := 0, ""
+	// Duplicates are only reported within r, so that a later load may
+	// override an earlier one.
+	seenSections := make(map[string]bool)
+	seenKeys := make(map[string]map[string]int)
+	// atStart reports whether only comments have been read so far, and
+	// inTOC whether they form a SetTOC table, which ends at a blank line.
+	atStart, inTOC := true, false
+
+	for scanner.Scan() {
+		lineNum++
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+
+		if line == "" {
+			inTOC = false
+			continue
+		}
+		if prefix, ok := p.sectionCommentPrefix(section, line); ok {
+			comment := strings.TrimSpace(line[len(prefix):])
+			if atStart && len(comments) == 0 && comment == "Sections:" {
+				inTOC = true
+			}
+			if !inTOC {
+				comments = append(comments, comment)
+			}
+			continue
+		}
+		atStart, inTOC = false, false
+
+		if path, ok := p.includePath(line); ok {
+			emptyLine = 0
+			if err := p.include(dir, path, section, inHeader); err != nil {
+				if err := p.lineError(lineNum, err); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if name, ok := p.sectionHeader(line); ok {
+			if p.flat {
+				if err := p.lineError(lineNum, ErrUnexpectedSection); err != nil {
+					return err
+				}
+				continue
+			}
+			name = p.unescapeSectionName(p.trimSectionName(name))
+			if name == "" {
+				if !p.allowEmptySection {
+					if err := p.lineError(lineNum, ErrEmptySectionName); err != nil {
+						return err
+					}
+					continue
+				}
+				// An empty header returns to the keys outside of any
+				// section rather than creating a section named "".
+				p.warnEmptySection(emptyLine, emptySection)
+				emptyLine = 0
+				p.warn(lineNum, "", "", "empty section header, the keys below it are global")
+				section = ""
+				inHeader = true
+				p.comments[""] = append(p.comments[""], comments...)
+				comments = nil
+				continue
+			}
+			section = name
+			inHeader = true
+			if err := p.checkSectionLimit(section); err != nil {
+				return fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			if seenSections[section] && !p.repeatable[section] {
+				p.conflict(lineNum, "duplicate section [%s] merged with the earlier one", section)
+			}
+			seenSections[section] = true
+			p.warnEmptySection(emptyLine, emptySection)
+			emptyLine, emptySection = lineNum, section
+			p.startBlock(section)
+			p.createSectionIfNotExist(section)
+			p.comments[section] = append(p.comments[section], comments...)
+			comments = nil
+			continue
+		}
+
+		if open, close := p.sectionDelimiters(); strings.HasPrefix(line, open) {
+			err := fmt.Errorf("%w %q: missing %q", ErrInvalidSectionHeader, line, close)
+			if err := p.lineError(lineNum, err); err != nil {
+				return err
+			}
+			comments = nil
+			continue
+		}
+
+		if !inHeader {
+			p.comments[section] = append(p.comments[section], comments...)
+		}
+		comments = nil
+
+		// The untrimmed line lets whitespace-only values be preserved.
+		key, value, err := p.parseKeyValue(raw, p.keyDelimiter(section))
+		rawValue := value
+		if err == nil && p.unquote {
+			value = unquoteValue(value)
+		}
+		if err == nil && p.encodeControl {
+			value, err = decodeControlChars(value)
+		}
+		if err == nil {
+			value, err = p.resolveFileReference(dir, value)
+		}
+		if errors.Is(err, ErrLimitExceeded) {
+			return fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		if err != nil {
+			if err := p.lineError(lineNum, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := p.checkKeyLimit(section, key); err != nil {
+			return fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		if p.transform != nil {
+			value = p.transform(section, key, value)
+		}
+
+		emptyLine = 0
+		p.lintValue(lineNum, section, key, value)
+		p.lintSpacing(lineNum, section, key, raw, rawValue)
+		p.checkDuplicateKey(seenKeys, lineNum, section, key)
+		previous := p.multiValues[p.resolveSection(section)][key]
+		p.setValue(section, key, value)
+		if p.multiKey {
+			p.storeValues(section, key, append(previous, value))
+		}
+		p.recordLine(section, key, lineNum)
+		p.recordRaw(section, key, rawValue)
+		if block, ok := p.currentBlock(section); ok {
+			block[key] = value
+		}
+	}
+
+	p.warnEmptySection(emptyLine, emptySection)
+	return scanner.Err()
+}
+
+// lineError attaches lineNum to err. When SetSkipErrors is enabled the error
+// is recorded and nil is returned so parsing can go on.
+func (p *Parser) lineError(lineNum int, err error) error {
+	err = fmt.Errorf("line %d: %w", lineNum, err)
+	if p.skipErrors {
+		p.errs = append(p.errs, err)
+		return nil
+	}
+	return err
+}
+
+// SetFlatMode treats the input as a flat list of KEY=VALUE lines, as in
+// environment files: every key is global, section headers are rejected with
+// ErrUnexpectedSection, and ToString writes no headers.
+func (p *Parser) SetFlatMode(flat bool) {
+	p.flat = flat
+}
+
+// SetAllowEmptyValues makes parsing accept keys without a value, such as
+// "key =", storing them as an empty string. Such keys exist: HasKey reports
+// true and Get returns ("", true).
+func (p *Parser) SetAllowEmptyValues(allow bool) {
+	p.allowEmpty = allow
+}
+
+// SetValueTransformer makes parsing store fn(section, key, value) in place
+// of each value, for example to decrypt secrets or normalize values. It runs
+// after quotes, escapes and file references are resolved, with an empty
+// section for keys outside of any section. GetRaw still returns the value as
+// written. A nil fn removes the transformer.
+func (p *Parser) SetValueTransformer(fn func(section, key, value string) string) {
+	p.transform = fn
+}
+
+// SetAllowEmptySectionName makes parsing accept a section header without a
+// name, such as [] or [ ], which switches back to the keys outside of any
+// section: keys that follow it are global, or belong to the default section
+// set by SetDefaultSectionName. By default such a header is rejected with
+// ErrEmptySectionName, as in "line 3: empty section name".
+func (p *Parser) SetAllowEmptySectionName(allow bool) {
+	p.allowEmptySection = allow
+}
+
+// SetPreserveSpaceValues keeps values made only of whitespace, so that
+// "key =   " yields three spaces instead of an empty value. It takes
+// precedence over SetAllowEmptyValues: such a value is kept as is even when
+// empty values are rejected, while "key =" with nothing after the delimiter
+// is still empty and only accepted with SetAllowEmptyValues. Other values
+// are trimmed as usual.
+func (p *Parser) SetPreserveSpaceValues(preserve bool) {
+	p.preserveSpace = preserve
+}
+
+// SetSkipErrors makes parsing skip malformed lines instead of failing. The
+// skipped lines are reported by Errors while the valid ones are loaded. A
+// skipped section header leaves the current section unchanged, so the keys
+// below it belong to the last valid section, or are globals if there was
+// none.
+func (p *Parser) SetSkipErrors(skip bool) {
+	p.skipErrors = skip
+}
+
+// Errors returns the errors of the lines skipped while SetSkipErrors was
+// enabled, in the order they were found.
+func (p *Parser) Errors() []error {
+	errs := make([]error, len(p.errs))
+	copy(errs, p.errs)
+	return errs
+}
+
+// defaultCommentPrefixes are the comment prefixes used unless
+// SetCommentPrefixes is called.
+var defaultCommentPrefixes = []string{";", "#"}
+
+// SetCommentPrefixes sets the prefixes that mark a line as a comment,
+// replacing the default ";" and "#". Prefixes are matched case-insensitively,
+// so "REM " accepts both "REM text" and "rem text" as found in legacy
+// Windows files. Calling it with no prefixes restores the defaults.
+func (p *Parser) SetCommentPrefixes(prefixes ...string) {
+	p.commentPrefixes = append([]string(nil), prefixes...)
+}
+
+// commentPrefix returns the comment prefix line starts with, if any.
+func (p *Parser) commentPrefix(line string) (string, bool) {
+	prefixes := p.commentPrefixes
+	if len(prefixes) == 0 {
+		prefixes = defaultCommentPrefixes
+	}
+	return matchCommentPrefix(line, prefixes)
+}
+
+// matchCommentPrefix returns the first of prefixes that the trimmed line
+// starts with, ignoring case. A line equal to a prefix without its trailing
+// whitespace, such as a bare REM for "REM ", is an empty comment; the
+// returned prefix is then trimmed to match the line.
+func matchCommentPrefix(line string, prefixes []string) (string, bool) {
+	for _, prefix := range prefixes {
+		if prefix == "" {
+			continue
+		}
+		if len(line) >= len(prefix) && strings.EqualFold(line[:len(prefix)], prefix) {
+			return prefix, true
+		}
+		if bare := strings.TrimRight(prefix, " \t"); bare != "" && strings.EqualFold(line, bare) {
+			return bare, true
+		}
+	}
+	return "", false
+}
+
+// SetSectionTrimCutset sets the characters trimmed from both ends of a
+// section name once its brackets are removed, so that a header such as
+// [ "name" ] can be read as name with the cutset ` "`. An empty cutset, the
+// default, trims whitespace.
+func (p *Parser) SetSectionTrimCutset(cutset string) {
+	p.sectionCutset = cutset
+}
+
+func (p *Parser) trimSectionName(name string) string {
+	if p.sectionCutset == "" {
+		return strings.TrimSpace(name)
+	}
+	return strings.Trim(name, p.sectionCutset)
+}
+
+// SetSectionDelimiters sets the strings that open and close a section
+// header, both when parsing and writing, so that headers such as <name> or
+// {name} can be used. Empty values restore the default "[" and "]".
+func (p *Parser) SetSectionDelimiters(open, close string) {
+	p.sectionOpen, p.sectionClose = open, close
+}
+
+func (p *Parser) sectionDelimiters() (string, string) {
+	if p.sectionOpen == "" || p.sectionClose == "" {
+		return "[", "]"
+	}
+	return p.sectionOpen, p.sectionClose
+}
+
+// sectionHeader returns the raw name inside line if line is a section
+// header.
+func (p *Parser) sectionHeader(line string) (string, bool) {
+	open, close := p.sectionDelimiters()
+	if len(line) < len(open)+len(close) || !strings.HasPrefix(line, open) || !strings.HasSuffix(line, close) {
+		return "", false
+	}
+	return line[len(open) : len(line)-len(close)], true
+}
+
+// escapeSectionName escapes the section delimiters inside name, which would
+// otherwise end the header early, so names such as "a]b" survive a round
+// trip.
+func (p *Parser) escapeSectionName(name string) string {
+	open, close := p.sectionDelimiters()
+	return strings.NewReplacer(`\`, `\\`, open, `\`+open, close, `\`+close).Replace(name)
+}
+
+// unescapeSectionName reverses escapeSectionName. Only \\ and a backslash
+// before a section delimiter are escapes; any other backslash is kept, so
+// names such as C:\dir\sub read as written.
+func (p *Parser) unescapeSectionName(name string) string {
+	if !strings.Contains(name, `\`) {
+		return name
+	}
+
+	open, close := p.sectionDelimiters()
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] == '\\' {
+			rest := name[i+1:]
+			switch {
+			case strings.HasPrefix(rest, `\`):
+				b.WriteByte('\\')
+				i++
+				continue
+			case strings.HasPrefix(rest, open):
+				b.WriteString(open)
+				i += len(open)
+				continue
+			case strings.HasPrefix(rest, close):
+				b.WriteString(close)
+				i += len(close)
+				continue
+			}
+		}
+		b.WriteByte(name[i])
+	}
+	return b.String()
+}
+
+func (p *Parser) parseKeyValue(line, delim string) (string, string, error) {
+	key, value, found := strings.Cut(line, delim)
+	if !found {
+		return "", "", ErrMissingDelimiter
+	}
+	if err := p.checkSpacing(key, value); err != nil {
+		return "", "", err
+	}
+
+	key = strings.TrimSpace(key)
+	if trimmed := strings.TrimSpace(value); trimmed != "" || !p.preserveSpace {
+		value = trimmed
+	}
+
+	if key == "" {
+		return "", "", ErrEmptyKey
+	}
+	if err := checkName(key); err != nil {
+		return "", "", err
+	}
+	if value == "" && !p.allowEmpty {
+		return "", "", fmt.Errorf("%w for key %q", ErrEmptyValue, key)
+	}
+	if err := p.checkValueLength(key, value); err != nil {
+		return "", "", err
+	}
+
+	return key, value, nil
+}
+
+// checkName rejects names containing characters that cannot be written back
+// on a single line.
+func checkName(name string) error {
+	if strings.ContainsAny(name, "\x00\r\n") {
+		return fmt.Errorf("%w %q: contains a NUL byte or line break", ErrInvalidName, name)
+	}
+	return nil
+}
+
+func (p *Parser) createSectionIfNotExist(section string) {
+	if _, ok := p.data[section]; ok {
+		return
+	}
+	p.data[section] = make(map[string]string)
+	p.sections = append(p.sections, section)
+}
+
+// SetDefaultSectionName stores keys that appear outside of any section under
+// the section called name (for example "DEFAULT") instead of as globals, so
+// they are reachable with Get(name, key) and written as a regular section.
+// An empty name restores the default behavior. Only keys added after the call
+// are affected.
+func (p *Parser) SetDefaultSectionName(name string) {
+	p.defaultSection = name
+}
+
+// SetRootSectionName makes keys that appear before the first section header
+// of a parsed file belong to the section called name, so that they are
+// written back under a [name] header. Unlike SetDefaultSectionName it only
+// affects parsing: Set and Get with an empty section still address globals.
+// An empty name restores the default behavior. It has no effect in flat
+// mode, where every key is global.
+func (p *Parser) SetRootSectionName(name string) {
+	p.rootSection = name
+}
+
+// initialSection returns the section that keys before the first header of a
+// parsed file belong to. Flat mode takes precedence over SetRootSectionName.
+func (p *Parser) initialSection() string {
+	if p.flat {
+		return ""
+	}
+	return p.rootSection
+}
+
+// resolveSection maps the empty section to the configured default section.
+func (p *Parser) resolveSection(section string) string {
+	if section == "" && !p.flat {
+		return p.defaultSection
+	}
+	return section
+}
+
+// setValue stores value under section and key, treating an empty section
+// as the global namespace.
+func (p *Parser) setValue(section, key, value string) {
+	section = p.resolveSection(section)
+	delete(p.multiValues[section], key)
+	delete(p.rawValues[section], key)
+	p.markDefault(section, key)
+	if section == "" {
+		if _, ok := p.globalKeys[key]; !ok {
+			p.globalOrder = append(p.globalOrder, key)
+		}
+		p.globalKeys[key] = value
+		return
+	}
+	p.createSectionIfNotExist(section)
+	if _, ok := p.data[section][key]; !ok {
+		p.keyOrder[section] = append(p.keyOrder[section], key)
+	}
+	p.data[section][key] = value
+}
+
+// deleteKey removes key from section, treating an empty section as the
+// global namespace. It reports whether the key existed.
+func (p *Parser) deleteKey(section, key string) bool {
+	section = p.resolveSection(section)
+	keys, ok := p.sectionKeys(section)
+	if !ok {
+		return false
+	}
+	if _, ok := keys[key]; !ok {
+		return false
+	}
+
+	delete(keys, key)
+	delete(p.keyLines[section], key)
+	delete(p.multiValues[section], key)
+	delete(p.rawValues[section], key)
+	delete(p.defaults[section], key)
+	if section == "" {
+		p.globalOrder = removeKey(p.globalOrder, key)
+	} else {
+		p.keyOrder[section] = removeKey(p.keyOrder[section], key)
+	}
+	return true
+}
+
+func (p *Parser) recordLine(section, key string, line int) {
+	section = p.resolveSection(section)
+	if p.keyLines[section] == nil {
+		p.keyLines[section] = make(map[string]int)
+	}
+	p.keyLines[section][key] = line
+}
+
+func (p *Parser) recordRaw(section, key, raw string) {
+	section = p.resolveSection(section)
+	if p.rawValues[section] == nil {
+		p.rawValues[section] = make(map[string]string)
+	}
+	p.rawValues[section][key] = raw
+}
+
+// GetRaw returns the value of key in section exactly as it was written in
+// the parsed source, before quotes were removed, escape sequences decoded or
+// other transformations applied. Values that were set rather than parsed
+// are returned as stored.
+func (p *Parser) GetRaw(section, key string) (string, bool) {
+	if raw, ok := p.rawValues[p.resolveSection(section)][key]; ok {
+		return raw, true
+	}
+	return p.Get(section, key)
+}
+
+// KeyLine returns the 1-based source line key was parsed from in section.
+// It reports false for keys that were not parsed, such as those added with
+// Set.
+func (p *Parser) KeyLine(section, key string) (int, bool) {
+	line, ok := p.keyLines[p.resolveSection(section)][key]
+	return line, ok
+}
+
+// GetSectionNames returns the section names in the order they were first seen.
+func (p *Parser) GetSectionNames() []string {
+	names := make([]string, len(p.sections))
+	copy(names, p.sections)
+	return names
+}
+
+// GetSections returns a copy of every section and its keys.
+func (p *Parser) GetSections() map[string]map[string]string {
+	sections := make(map[string]map[string]string, len(p.data))
+	for name, keys := range p.data {
+		sections[name] = copyMap(keys)
+	}
+	return sections
+}
+
+// Section returns a copy of the keys of section, or an empty map if the
+// section does not exist, so the result can always be ranged over or
+// written to without affecting the parser. An empty section refers to the
+// global keys.
+func (p *Parser) Section(section string) map[string]string {
+	keys, _ := p.sectionKeys(section)
+	return copyMap(keys)
+}
+
+// Entry is a single key/value pair together with its section. Global keys
+// have an empty Section.
+type Entry struct {
+	Section, Key, Value string
+}
+
+// Entries returns every key/value pair of the parser: global keys sorted by
+// name first, then each section in order with its keys in the order they were
+// first set.
+func (p *Parser) Entries() []Entry {
+	var entries []Entry
+	for _, key := range sortedKeys(p.globalKeys) {
+		entries = append(entries, Entry{Key: key, Value: p.globalKeys[key]})
+	}
+	for _, section := range p.sections {
+		for _, key := range p.keyOrder[section] {
+			entries = append(entries, Entry{Section: section, Key: key, Value: p.data[section][key]})
+		}
+	}
+	return entries
+}
+
+// KeyValue is a single key/value pair.
+type KeyValue struct {
+	Key, Value string
+}
+
+// GetSectionOrdered returns the keys of section with their values in the
+// order they were first set, which for a parsed file is the source order. An
+// empty section refers to the global keys. It reports false if the section
+// does not exist.
+func (p *Parser) GetSectionOrdered(section string) ([]KeyValue, bool) {
+	keys, ok := p.sectionKeys(section)
+	if !ok {
+		return nil, false
+	}
+	order := p.keyOrderOf(section)
+	pairs := make([]KeyValue, 0, len(order))
+	for _, key := range order {
+		pairs = append(pairs, KeyValue{Key: key, Value: keys[key]})
+	}
+	return pairs, true
+}
+
+// keyOrderOf returns the key order of section, where the empty section
+// refers to the global keys.
+func (p *Parser) keyOrderOf(section string) []string {
+	section = p.resolveSection(section)
+	if section == "" {
+		return p.globalOrder
+	}
+	return p.keyOrder[section]
+}
+
+// GetGlobalKeys returns a copy of the keys defined before the first section.
+func (p *Parser) GetGlobalKeys() map[string]string {
+	return copyMap(p.globalKeys)
+}
+
+// GetGlobalKeysOrdered returns the keys defined before the first section
+// with their values, in the order they were first set.
+func (p *Parser) GetGlobalKeysOrdered() []KeyValue {
+	pairs := make([]KeyValue, 0, len(p.globalOrder))
+	for _, key := range p.globalOrder {
+		pairs = append(pairs, KeyValue{Key: key, Value: p.globalKeys[key]})
+	}
+	return pairs
+}
+
+// Get returns the value of key in section. An empty section refers to the
+// global keys.
+func (p *Parser) Get(section, key string) (string, bool) {
+	keys, ok := p.sectionKeys(section)
+	if !ok {
+		return "", false
+	}
+	value, ok := keys[key]
+	return value, ok
+}
+
+// sectionKeys returns the live key map of section, where the empty section
+// refers to the global keys.
+func (p *Parser) sectionKeys(section string) (map[string]string, bool) {
+	section = p.resolveSection(section)
+	if section == "" {
+		return p.globalKeys, true
+	}
+	keys, ok := p.data[section]
+	return keys, ok
+}
+
+// GetComments returns the comment lines written above the header of section,
+// without their comment prefix. The empty section returns the comments that
+// precede the global keys. Comments placed between keys are not kept.
+func (p *Parser) GetComments(section string) []string {
+	comments := p.comments[section]
+	if len(comments) == 0 {
+		return nil
+	}
+	c := make([]string, len(comments))
+	copy(c, comments)
+	return c
+}
+
+// HasGlobals reports whether any key is stored outside of a section.
+func (p *Parser) HasGlobals() bool {
+	return len(p.globalKeys) > 0
+}
+
+// SetForbidGlobals makes WriteTo and SaveToFile fail with
+// ErrUnexpectedGlobals when global keys exist, for consumers that expect
+// every key to live in a section.
+func (p *Parser) SetForbidGlobals(forbid bool) {
+	p.forbidGlobals = forbid
+}
+
+// HasSection reports whether section exists.
+func (p *Parser) HasSection(section string) bool {
+	_, ok := p.data[section]
+	return ok
+}
+
+// HasKey reports whether key exists in section.
+func (p *Parser) HasKey(section, key string) bool {
+	_, ok := p.Get(section, key)
+	return ok
+}
+
+// Set stores value under section and key, creating the section if needed.
+// An empty section sets a global key.
+func (p *Parser) Set(section, key, value string) error {
+	if err := p.checkSet(section, key); err != nil {
+		return err
+	}
+
+	p.setValue(section, key, value)
+	p.dirty = true
+	return nil
+}
+
+// checkSet reports why Set would reject section and key, if it would.
+func (p *Parser) checkSet(section, key string) error {
+	if strings.TrimSpace(key) == "" {
+		return ErrEmptyKey
+	}
+	if err := checkName(key); err != nil {
+		return err
+	}
+	if err := checkName(section); err != nil {
+		return err
+	}
+	if p.flat && section != "" {
+		return fmt.Errorf("%w: %s", ErrUnexpectedSection, section)
+	}
+	return nil
+}
+
+// Update sets the value of a key that already exists in section, failing
+// with ErrKeyNotFound otherwise, so that a misspelt key is not silently
+// created.
+func (p *Parser) Update(section, key, value string) error {
+	if !p.HasKey(section, key) {
+		return fmt.Errorf("%w: [%s] %s", ErrKeyNotFound, section, key)
+	}
+	return p.Set(section, key, value)
+}
+
+// With sets value under section and key like Set and returns p, so that
+// configurations can be built fluently:
+//
+//	NewParser().With("s", "a", "1").With("s", "b", "2")
+//
+// Invalid keys are silently skipped; use Set when the error matters.
+func (p *Parser) With(section, key, value string) *Parser {
+	_ = p.Set(section, key, value)
+	return p
+}
+
+// ToString serializes the parser into INI format. Global keys come first, in
+// the order they were first set, followed by each section in the order it
+// was first seen. Keys within a section are sorted. Lines end with the
+// terminator set by SetLineEnding.
+func (p *Parser) ToString() string {
+	var b strings.Builder
+
+	p.writeTOC(&b, p.sections)
+	p.writeOrderedKeys(&b, "", p.globalKeys, p.globalOrder)
+	for _, section := range p.sections {
+		p.writeSection(&b, section, sortedKeys(p.data[section]))
+	}
+
+	return b.String()
+}
+
+// ToStringOrdered is like ToString but writes the sections named in order
+// first, in that order, followed by the remaining sections in the order
+// they were first seen; the SetTOC comment lists them the same way. Names
+// of missing sections and repeated names are ignored. The section order of
+// the parser itself is not changed.
+func (p *Parser) ToStringOrdered(order []string) string {
+	sections := make([]string, 0, len(p.sections))
+	seen := make(map[string]bool, len(p.sections))
+	for _, names := range [][]string{order, p.sections} {
+		for _, section := range names {
+			if !seen[section] && p.HasSection(section) {
+				seen[section] = true
+				sections = append(sections, section)
+			}
+		}
+	}
+
+	var b strings.Builder
+
+	p.writeTOC(&b, sections)
+	p.writeOrderedKeys(&b, "", p.globalKeys, p.globalOrder)
+	for _, section := range sections {
+		p.writeSection(&b, section, sortedKeys(p.data[section]))
+	}
+
+	return b.String()
+}
+
+// SetTOC makes ToString start with a comment listing the current section
+// names in order, as an index for large files:
+//
+//	; Sections:
+//	;   server
+//	;   database
+//
+// The comment uses the first prefix set by SetCommentPrefixes and is left
+// out when there are no sections. When parsing, such a table at the start of
+// the input is skipped rather than kept as a comment of the first section.
+func (p *Parser) SetTOC(toc bool) {
+	p.toc = toc
+}
+
+// writeTOC writes the SetTOC comment listing sections.
+func (p *Parser) writeTOC(b *strings.Builder, sections []string) {
+	if !p.toc || len(sections) == 0 {
+		return
+	}
+	prefix := p.sectionCommentPrefixes("")[0]
+	eol := p.lineEnding()
+	fmt.Fprintf(b, "%s Sections:%s", prefix, eol)
+	for _, section := range sections {
+		fmt.Fprintf(b, "%s   %s%s", prefix, section, eol)
+	}
+	if len(p.globalKeys) > 0 {
+		// Sections are already preceded by a blank line.
+		b.WriteString(eol)
+	}
+}
+
+// ToStringCompact is like ToString but without the trailing line ending,
+// which is convenient when embedding the output inside other documents. An
+// empty parser yields an empty string.
+func (p *Parser) ToStringCompact() string {
+	return strings.TrimSuffix(p.ToString(), p.lineEnding())
+}
+
+// ToStringCanonical serializes the parser in a form suited to diffing and
+// version control. Global keys are sorted by name and sections are sorted by
+// name, while keys within a section keep the order they were first set in.
+// Equal parsers whose globals or sections were added in a different order
+// therefore produce identical output.
+func (p *Parser) ToStringCanonical() string {
+	var b strings.Builder
+
+	p.writeKeys(&b, p.globalKeys)
+
+	sections := p.GetSectionNames()
+	sort.Strings(sections)
+	for _, section := range sections {
+		p.writeSection(&b, section, p.keyOrder[section])
+	}
+
+	return b.String()
+}
+
+// SetLineEnding sets the line terminator used when writing, such as "\r\n"
+// for Windows consumers. An empty eol restores the default "\n".
+func (p *Parser) SetLineEnding(eol string) {
+	p.eol = eol
+}
+
+func (p *Parser) lineEnding() string {
+	if p.eol == "" {
+		return "\n"
+	}
+	return p.eol
+}
+
+// writeSection writes the header of section followed by its keys in order,
+// separated from any previous output by a blank line.
+func (p *Parser) writeSection(b *strings.Builder, section string, order []string) {
+	eol := p.lineEnding()
+	if b.Len() > 0 {
+		fmt.Fprintf(b, "%s", eol)
+	}
+	open, close := p.sectionDelimiters()
+	fmt.Fprintf(b, "%s%s%s%s", open, p.escapeSectionName(section), close, eol)
+	p.writeOrderedKeys(b, section, p.data[section], order)
+}
+
+func (p *Parser) writeKeys(b *strings.Builder, keys map[string]string) {
+	p.writeOrderedKeys(b, "", keys, sortedKeys(keys))
+}
+
+func (p *Parser) writeOrderedKeys(b *strings.Builder, section string, keys map[string]string, order []string) {
+	eol := p.lineEnding()
+	delim := p.keyDelimiter(section)
+	space := " "
+	if p.spacing == DelimiterSpacingNone {
+		space = ""
+	}
+	for _, key := range order {
+		value := p.formatValue(section, keys[key])
+		after := space
+		if strings.TrimSpace(value) == "" {
+			// No separating space, so that empty and whitespace-only
+			// values read back unchanged.
+			after = ""
+		}
+		fmt.Fprintf(b, "%s%s%s%s%s%s", key, space, delim, after, value, eol)
+	}
+}
+
+// formatValue prepares value, written in section, for output.
+func (p *Parser) formatValue(section, value string) string {
+	if p.encodeControl {
+		value = encodeControlChars(value)
+	}
+	if p.unquote && p.needsQuotes(section, value) {
+		value = `"` + EscapeValue(value) + `"`
+	}
+	return value
+}
+
+// WriteTo writes the serialized parser to w.
+func (p *Parser) WriteTo(w io.Writer) (int64, error) {
+	if err := p.checkGlobals(); err != nil {
+		return 0, err
+	}
+	n, err := io.WriteString(w, p.ToString())
+	return int64(n), err
+}
+
+// SaveToFile writes the serialized parser to the file at path.
+func (p *Parser) SaveToFile(path string) error {
+	if err := p.checkGlobals(); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(p.ToString()), 0o644); err != nil {
+		return err
+	}
+	p.dirty = false
+	return nil
+}
+
+// IsDirty reports whether the content was modified through methods such as
+// Set, Merge or TransformKeys since it was last loaded with ParseFile or
+// saved with SaveToFile. Parsing never marks the parser dirty.
+func (p *Parser) IsDirty() bool {
+	return p.dirty
+}
+
+func (p *Parser) checkGlobals() error {
+	if p.forbidGlobals && p.HasGlobals() {
+		return fmt.Errorf("%w: %s", ErrUnexpectedGlobals, strings.Join(sortedKeys(p.globalKeys), ", "))
+	}
+	return nil
+}
+
+// removeKey returns order without key, leaving the original slice intact.
+func removeKey(order []string, key string) []string {
+	for i, k := range order {
+		if k == key {
+			return append(order[:i:i], order[i+1:]...)
+		}
+	}
+	return order
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func copyMap(m map[string]string) map[string]string {
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
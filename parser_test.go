@@ -0,0 +1,1122 @@
+package iniparser
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleINI = `; global settings
+name = demo
+
+[server]
+host = localhost
+port = 8080
+
+# database settings
+[database]
+user = admin
+password = secret
+`
+
+func loadSample(t *testing.T) *Parser {
+	t.Helper()
+
+	p := NewParser()
+	if err := p.LoadFromString(sampleINI); err != nil {
+		t.Fatalf("LoadFromString: %v", err)
+	}
+	return p
+}
+
+func TestLoadFromString(t *testing.T) {
+	p := loadSample(t)
+
+	t.Run("section names", func(t *testing.T) {
+		want := []string{"server", "database"}
+		if got := p.GetSectionNames(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("sections", func(t *testing.T) {
+		want := map[string]map[string]string{
+			"server":   {"host": "localhost", "port": "8080"},
+			"database": {"user": "admin", "password": "secret"},
+		}
+		if got := p.GetSections(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("global keys", func(t *testing.T) {
+		want := map[string]string{"name": "demo"}
+		if got := p.GetGlobalKeys(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
+
+func TestMalformedInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{"missing delimiter", "[s]\nkey", ErrMissingDelimiter},
+		{"empty key", "[s]\n= value", ErrEmptyKey},
+		{"empty value", "[s]\nkey =", ErrEmptyValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewParser().LoadFromString(tt.input)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	p := loadSample(t)
+
+	tests := []struct {
+		section, key string
+		want         string
+		ok           bool
+	}{
+		{"server", "host", "localhost", true},
+		{"", "name", "demo", true},
+		{"server", "missing", "", false},
+		{"missing", "host", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := p.Get(tt.section, tt.key)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Get(%q, %q) = (%q, %v), want (%q, %v)", tt.section, tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	p := NewParser()
+
+	if err := p.Set("server", "port", "9090"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if got, _ := p.Get("server", "port"); got != "9090" {
+		t.Errorf("got %q, want %q", got, "9090")
+	}
+
+	if err := p.Set("server", " ", "x"); !errors.Is(err, ErrEmptyKey) {
+		t.Errorf("got %v, want %v", err, ErrEmptyKey)
+	}
+}
+
+func TestToString(t *testing.T) {
+	p := loadSample(t)
+
+	want := `name = demo
+
+[server]
+host = localhost
+port = 8080
+
+[database]
+password = secret
+user = admin
+`
+	if got := p.ToString(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestToStringCompact(t *testing.T) {
+	p := loadSample(t)
+
+	full := p.ToString()
+	compact := p.ToStringCompact()
+	if full != compact+"\n" {
+		t.Errorf("ToStringCompact = %q, want %q without its final newline", compact, full)
+	}
+
+	if got := NewParser().ToStringCompact(); got != "" {
+		t.Errorf("empty parser: got %q, want empty string", got)
+	}
+}
+
+func TestSaveToFile(t *testing.T) {
+	p := loadSample(t)
+	path := filepath.Join(t.TempDir(), "config.ini")
+
+	if err := p.SaveToFile(path); err != nil {
+		t.Fatalf("SaveToFile: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != p.ToString() {
+		t.Errorf("file content does not match ToString")
+	}
+
+	loaded := NewParser()
+	if err := loaded.ParseFile(path); err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.GetSections(), p.GetSections()) {
+		t.Errorf("round trip mismatch: got %v, want %v", loaded.GetSections(), p.GetSections())
+	}
+}
+
+func TestSectionNameEscaping(t *testing.T) {
+	names := []string{"a]b", "[x]", `back\slash`}
+
+	p := NewParser()
+	for _, name := range names {
+		if err := p.Set(name, "key", "value"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := p.ToString()
+	if !strings.Contains(out, `[a\]b]`) {
+		t.Errorf("output %q does not escape the bracket", out)
+	}
+
+	loaded := NewParser()
+	if err := loaded.LoadFromString(out); err != nil {
+		t.Fatalf("LoadFromString: %v", err)
+	}
+	if got := loaded.GetSectionNames(); !reflect.DeepEqual(got, names) {
+		t.Errorf("got %q, want %q", got, names)
+	}
+	if got, _ := loaded.Get("a]b", "key"); got != "value" {
+		t.Errorf("got %q, want %q", got, "value")
+	}
+}
+
+func TestToStringCanonical(t *testing.T) {
+	first := NewParser()
+	if err := first.LoadFromString("b = 2\na = 1\n[zeta]\nz = 1\ny = 2\n[alpha]\nk = v\n"); err != nil {
+		t.Fatal(err)
+	}
+	second := NewParser()
+	if err := second.LoadFromString("a = 1\n[alpha]\nk = v\n[zeta]\nz = 1\ny = 2\nb = 3\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Set("", "b", "2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Set("zeta", "b", "3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Set("zeta", "b", "3"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `a = 1
+b = 2
+
+[alpha]
+k = v
+
+[zeta]
+z = 1
+y = 2
+b = 3
+`
+	if got := first.ToStringCanonical(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if first.ToStringCanonical() != first.ToStringCanonical() {
+		t.Error("output differs between calls")
+	}
+	if got := second.ToStringCanonical(); got != want {
+		t.Errorf("reordered parser: got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGetComments(t *testing.T) {
+	p := loadSample(t)
+
+	tests := []struct {
+		section string
+		want    []string
+	}{
+		{"", []string{"global settings"}},
+		{"database", []string{"database settings"}},
+		{"server", nil},
+	}
+
+	for _, tt := range tests {
+		if got := p.GetComments(tt.section); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetComments(%q) = %q, want %q", tt.section, got, tt.want)
+		}
+	}
+}
+
+func TestSetDefaultSectionName(t *testing.T) {
+	p := NewParser()
+	p.SetDefaultSectionName("DEFAULT")
+	if err := p.LoadFromString("name = demo\n[server]\nport = 80\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := p.Get("DEFAULT", "name"); !ok || got != "demo" {
+		t.Errorf("Get(DEFAULT, name) = (%q, %v), want (%q, true)", got, ok, "demo")
+	}
+	if got, ok := p.Get("", "name"); !ok || got != "demo" {
+		t.Errorf("Get(\"\", name) = (%q, %v), want (%q, true)", got, ok, "demo")
+	}
+	if got := p.GetGlobalKeys(); len(got) != 0 {
+		t.Errorf("GetGlobalKeys() = %v, want empty", got)
+	}
+
+	want := "[DEFAULT]\nname = demo\n\n[server]\nport = 80\n"
+	if got := p.ToString(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestHasGlobals(t *testing.T) {
+	t.Run("with globals", func(t *testing.T) {
+		p := loadSample(t)
+		if !p.HasGlobals() {
+			t.Error("HasGlobals() = false, want true")
+		}
+
+		p.SetForbidGlobals(true)
+		var b strings.Builder
+		if _, err := p.WriteTo(&b); !errors.Is(err, ErrUnexpectedGlobals) {
+			t.Errorf("WriteTo: got %v, want %v", err, ErrUnexpectedGlobals)
+		}
+	})
+
+	t.Run("without globals", func(t *testing.T) {
+		p := NewParser()
+		if err := p.LoadFromString("[server]\nport = 80\n"); err != nil {
+			t.Fatal(err)
+		}
+		if p.HasGlobals() {
+			t.Error("HasGlobals() = true, want false")
+		}
+
+		p.SetForbidGlobals(true)
+		var b strings.Builder
+		if _, err := p.WriteTo(&b); err != nil {
+			t.Errorf("WriteTo: %v", err)
+		}
+	})
+}
+
+func TestParseBytes(t *testing.T) {
+	fromBytes := NewParser()
+	if err := fromBytes.ParseBytes([]byte(sampleINI)); err != nil {
+		t.Fatalf("ParseBytes: %v", err)
+	}
+
+	fromString := loadSample(t)
+	if got, want := fromBytes.ToString(), fromString.ToString(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestEntries(t *testing.T) {
+	p := loadSample(t)
+
+	want := []Entry{
+		{Key: "name", Value: "demo"},
+		{Section: "server", Key: "host", Value: "localhost"},
+		{Section: "server", Key: "port", Value: "8080"},
+		{Section: "database", Key: "user", Value: "admin"},
+		{Section: "database", Key: "password", Value: "secret"},
+	}
+	if got := p.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSetSectionTrimCutset(t *testing.T) {
+	p := NewParser()
+	p.SetSectionTrimCutset(" \t\"")
+	if err := p.LoadFromString("[ \"name\" ]\nkey = value\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := p.GetSectionNames(); !reflect.DeepEqual(got, []string{"name"}) {
+		t.Errorf("got %q, want %q", got, []string{"name"})
+	}
+}
+
+func TestKeyLine(t *testing.T) {
+	p := loadSample(t)
+	if err := p.Set("server", "timeout", "5s"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		section, key string
+		want         int
+		ok           bool
+	}{
+		{"", "name", 2, true},
+		{"server", "host", 5, true},
+		{"server", "port", 6, true},
+		{"database", "password", 11, true},
+		{"server", "timeout", 0, false},
+		{"server", "missing", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := p.KeyLine(tt.section, tt.key)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("KeyLine(%q, %q) = (%d, %v), want (%d, %v)", tt.section, tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestInvalidNames(t *testing.T) {
+	p := NewParser()
+
+	tests := []struct{ section, key string }{
+		{"s", "bad\nkey"},
+		{"s", "bad\x00key"},
+		{"bad\nsection", "key"},
+	}
+	for _, tt := range tests {
+		if err := p.Set(tt.section, tt.key, "v"); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Set(%q, %q): got %v, want %v", tt.section, tt.key, err, ErrInvalidName)
+		}
+	}
+
+	if err := p.Set("s", "good_key", "v"); err != nil {
+		t.Errorf("Set with a valid key: %v", err)
+	}
+
+	if err := NewParser().LoadFromString("[s]\nbad\x00key = v\n"); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("LoadFromString: got %v, want %v", err, ErrInvalidName)
+	}
+}
+
+func TestSetLineEnding(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("name = demo\n[server]\nport = 80\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "name = demo\n\n[server]\nport = 80\n"; p.ToString() != want {
+		t.Errorf("default: got %q, want %q", p.ToString(), want)
+	}
+
+	p.SetLineEnding("\r\n")
+	if want := "name = demo\r\n\r\n[server]\r\nport = 80\r\n"; p.ToString() != want {
+		t.Errorf("CRLF: got %q, want %q", p.ToString(), want)
+	}
+	if want := "name = demo\r\n\r\n[server]\r\nport = 80"; p.ToStringCompact() != want {
+		t.Errorf("CRLF compact: got %q, want %q", p.ToStringCompact(), want)
+	}
+}
+
+func TestWith(t *testing.T) {
+	p := NewParser().With("s", "a", "1").With("s", "b", "2")
+
+	if want := "[s]\na = 1\nb = 2\n"; p.ToString() != want {
+		t.Errorf("got %q, want %q", p.ToString(), want)
+	}
+}
+
+func TestSetCommentPrefixes(t *testing.T) {
+	input := "REM this is a comment\n[s]\nrem another comment\nREM\n  rem  \nkey = value\n"
+
+	p := NewParser()
+	p.SetCommentPrefixes(";", "#", "REM ")
+	if err := p.LoadFromString(input); err != nil {
+		t.Fatalf("LoadFromString: %v", err)
+	}
+	if got := p.GetSections(); !reflect.DeepEqual(got, map[string]map[string]string{"s": {"key": "value"}}) {
+		t.Errorf("got %v", got)
+	}
+	if got := p.GetComments("s"); !reflect.DeepEqual(got, []string{"this is a comment"}) {
+		t.Errorf("GetComments = %q", got)
+	}
+
+	if err := NewParser().LoadFromString(input); !errors.Is(err, ErrMissingDelimiter) {
+		t.Errorf("without the REM prefix: got %v, want %v", err, ErrMissingDelimiter)
+	}
+}
+
+func TestSetSkipErrors(t *testing.T) {
+	p := NewParser()
+	p.SetSkipErrors(true)
+
+	err := p.LoadFromString("[s]\ngood = 1\nbroken\n= nokey\nalso_good = 2\n")
+	if err != nil {
+		t.Fatalf("LoadFromString: %v", err)
+	}
+
+	if got, want := p.GetSections(), map[string]map[string]string{"s": {"good": "1", "also_good": "2"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	errs := p.Errors()
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors, want 2: %v", len(errs), errs)
+	}
+	if !errors.Is(errs[0], ErrMissingDelimiter) || !strings.HasPrefix(errs[0].Error(), "line 3:") {
+		t.Errorf("errs[0] = %v", errs[0])
+	}
+	if !errors.Is(errs[1], ErrEmptyKey) || !strings.HasPrefix(errs[1].Error(), "line 4:") {
+		t.Errorf("errs[1] = %v", errs[1])
+	}
+}
+
+func TestSetSectionDelimiters(t *testing.T) {
+	p := NewParser()
+	p.SetSectionDelimiters("<", ">")
+	if err := p.LoadFromString("<server>\nhost = localhost\n"); err != nil {
+		t.Fatalf("LoadFromString: %v", err)
+	}
+	if err := p.Set("a>b", "key", "value"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := p.Get("server", "host"); got != "localhost" {
+		t.Errorf("got %q, want %q", got, "localhost")
+	}
+
+	out := p.ToString()
+	if want := "<server>\nhost = localhost\n\n<a\\>b>\nkey = value\n"; out != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+
+	loaded := NewParser()
+	loaded.SetSectionDelimiters("<", ">")
+	if err := loaded.LoadFromString(out); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if got := loaded.GetSectionNames(); !reflect.DeepEqual(got, []string{"server", "a>b"}) {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestParseDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"10-base.ini":     "[server]\nhost = localhost\nport = 80\n",
+		"20-override.ini": "[server]\nport = 8080\n",
+		"05-notes.txt":    "not an ini file",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := NewParser()
+	if err := p.ParseDir(dir); err != nil {
+		t.Fatalf("ParseDir: %v", err)
+	}
+	want := map[string]map[string]string{"server": {"host": "localhost", "port": "8080"}}
+	if got := p.GetSections(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "30-broken.ini"), []byte("broken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewParser().ParseDir(dir); !errors.Is(err, ErrMissingDelimiter) {
+		t.Errorf("broken file: got %v, want %v", err, ErrMissingDelimiter)
+	}
+	if err := NewParser().ParseDir(filepath.Join(dir, "missing")); err == nil {
+		t.Error("missing directory: got nil error")
+	}
+}
+
+func TestCommentForms(t *testing.T) {
+	for _, comment := range []string{"#", "# x", "#x", ";", "; x", ";x"} {
+		t.Run(comment, func(t *testing.T) {
+			p := NewParser()
+			if err := p.LoadFromString("[s]\n" + comment + "\nkey = value\n"); err != nil {
+				t.Fatalf("LoadFromString: %v", err)
+			}
+			want := map[string]map[string]string{"s": {"key": "value"}}
+			if got := p.GetSections(); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadFromReaders(t *testing.T) {
+	defaults := strings.NewReader("[server]\nhost = localhost\nport = 80")
+	override := strings.NewReader("[server]\nport = 8080\n")
+
+	p := NewParser()
+	if err := p.LoadFromReaders(defaults, override); err != nil {
+		t.Fatalf("LoadFromReaders: %v", err)
+	}
+
+	want := map[string]map[string]string{"server": {"host": "localhost", "port": "8080"}}
+	if got := p.GetSections(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSetAllowEmptyValues(t *testing.T) {
+	p := NewParser()
+	p.SetAllowEmptyValues(true)
+	if err := p.LoadFromString("[s]\nempty =\n"); err != nil {
+		t.Fatalf("LoadFromString: %v", err)
+	}
+
+	if !p.HasKey("s", "empty") {
+		t.Error("HasKey(empty) = false, want true")
+	}
+	if got, ok := p.Get("s", "empty"); got != "" || !ok {
+		t.Errorf("Get(empty) = (%q, %v), want (\"\", true)", got, ok)
+	}
+	if p.HasKey("s", "absent") {
+		t.Error("HasKey(absent) = true, want false")
+	}
+	if got, ok := p.Get("s", "absent"); got != "" || ok {
+		t.Errorf("Get(absent) = (%q, %v), want (\"\", false)", got, ok)
+	}
+}
+
+// stallingReader blocks on Read until it is closed.
+type stallingReader struct {
+	closed chan struct{}
+}
+
+func (r *stallingReader) Read([]byte) (int, error) {
+	<-r.closed
+	return 0, io.EOF
+}
+
+func (r *stallingReader) Close() error {
+	close(r.closed)
+	return nil
+}
+
+func TestLoadFromReaderTimeout(t *testing.T) {
+	t.Run("fast reader", func(t *testing.T) {
+		p := NewParser()
+		if err := p.LoadFromReaderTimeout(strings.NewReader(sampleINI), time.Second); err != nil {
+			t.Fatalf("LoadFromReaderTimeout: %v", err)
+		}
+		if got, _ := p.Get("server", "port"); got != "8080" {
+			t.Errorf("got %q, want %q", got, "8080")
+		}
+	})
+
+	t.Run("slow reader", func(t *testing.T) {
+		r := &stallingReader{closed: make(chan struct{})}
+		err := NewParser().LoadFromReaderTimeout(r, 10*time.Millisecond)
+		if !errors.Is(err, ErrTimeout) {
+			t.Fatalf("got %v, want %v", err, ErrTimeout)
+		}
+
+		select {
+		case <-r.closed:
+		default:
+			t.Error("reader was not closed after the timeout")
+		}
+	})
+}
+
+func TestSetFlatMode(t *testing.T) {
+	p := NewParser()
+	p.SetFlatMode(true)
+	p.SetDefaultSectionName("DEFAULT")
+	if err := p.LoadFromString("# app environment\nPORT=8080\nHOST=localhost\n"); err != nil {
+		t.Fatalf("LoadFromString: %v", err)
+	}
+
+	if want := map[string]string{"PORT": "8080", "HOST": "localhost"}; !reflect.DeepEqual(p.GetGlobalKeys(), want) {
+		t.Errorf("got %v, want %v", p.GetGlobalKeys(), want)
+	}
+	if want := "PORT = 8080\nHOST = localhost\n"; p.ToString() != want {
+		t.Errorf("got %q, want %q", p.ToString(), want)
+	}
+
+	if err := p.Set("server", "port", "1"); !errors.Is(err, ErrUnexpectedSection) {
+		t.Errorf("Set: got %v, want %v", err, ErrUnexpectedSection)
+	}
+	if err := p.LoadFromString("[server]\nport = 1\n"); !errors.Is(err, ErrUnexpectedSection) {
+		t.Errorf("LoadFromString: got %v, want %v", err, ErrUnexpectedSection)
+	}
+
+	other := loadSample(t)
+	if err := p.Merge(other); !errors.Is(err, ErrUnexpectedSection) {
+		t.Errorf("Merge: got %v, want %v", err, ErrUnexpectedSection)
+	}
+	if err := p.MergeSection(other, "server"); !errors.Is(err, ErrUnexpectedSection) {
+		t.Errorf("MergeSection: got %v, want %v", err, ErrUnexpectedSection)
+	}
+	if err := p.InsertSection("server", 0); !errors.Is(err, ErrUnexpectedSection) {
+		t.Errorf("InsertSection: got %v, want %v", err, ErrUnexpectedSection)
+	}
+	if names := p.GetSectionNames(); len(names) != 0 {
+		t.Errorf("sections = %v, want none", names)
+	}
+	if p.HasKey("", "name") {
+		t.Error("failed Merge copied global keys")
+	}
+}
+
+func TestSetRootSectionNameFlatMode(t *testing.T) {
+	p := NewParser()
+	p.SetRootSectionName("main")
+	p.SetFlatMode(true)
+	if err := p.LoadFromString("PORT = 8080\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := p.Get("", "PORT"); !ok || got != "8080" {
+		t.Errorf("got %q, %v, want a global %q", got, ok, "8080")
+	}
+	if names := p.GetSectionNames(); len(names) != 0 {
+		t.Errorf("sections = %v, want none", names)
+	}
+}
+
+func TestSetRootSectionName(t *testing.T) {
+	p := NewParser()
+	p.SetRootSectionName("main")
+	if err := p.LoadFromString("; main settings\nname = demo\n[server]\nport = 80\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := p.Get("main", "name"); !ok || got != "demo" {
+		t.Errorf("Get(main, name) = (%q, %v), want (%q, true)", got, ok, "demo")
+	}
+	if p.HasGlobals() {
+		t.Error("leading keys were stored as globals")
+	}
+	if got := p.GetComments("main"); !reflect.DeepEqual(got, []string{"main settings"}) {
+		t.Errorf("GetComments(main) = %q", got)
+	}
+	if want := "[main]\nname = demo\n\n[server]\nport = 80\n"; p.ToString() != want {
+		t.Errorf("got %q, want %q", p.ToString(), want)
+	}
+}
+
+func TestIsDirty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.ini")
+	if err := os.WriteFile(path, []byte(sampleINI), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewParser()
+	if err := p.ParseFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if p.IsDirty() {
+		t.Error("dirty after a pure load")
+	}
+
+	if err := p.Set("server", "port", "9090"); err != nil {
+		t.Fatal(err)
+	}
+	if !p.IsDirty() {
+		t.Error("not dirty after Set")
+	}
+
+	if err := p.SaveToFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if p.IsDirty() {
+		t.Error("dirty after SaveToFile")
+	}
+
+	if err := p.Merge(NewParser().With("cache", "size", "64")); err != nil {
+		t.Fatal(err)
+	}
+	if !p.IsDirty() {
+		t.Error("not dirty after Merge")
+	}
+}
+
+func TestWhitespaceOnlyValues(t *testing.T) {
+	tests := []struct {
+		name                 string
+		allowEmpty, preserve bool
+		input                string
+		want                 string
+		err                  error
+	}{
+		{"default spaces", false, false, "k =   ", "", ErrEmptyValue},
+		{"allow empty spaces", true, false, "k =   ", "", nil},
+		{"preserve spaces", false, true, "k =   ", "   ", nil},
+		{"both spaces", true, true, "k =   ", "   ", nil},
+		{"preserve nothing", false, true, "k =", "", ErrEmptyValue},
+		{"both nothing", true, true, "k =", "", nil},
+		{"preserve trims values", false, true, "k =  v  ", "v", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser()
+			p.SetAllowEmptyValues(tt.allowEmpty)
+			p.SetPreserveSpaceValues(tt.preserve)
+
+			err := p.LoadFromString("[s]\n" + tt.input + "\n")
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if got, _ := p.Get("s", "k"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			reloaded := NewParser()
+			reloaded.SetAllowEmptyValues(tt.allowEmpty)
+			reloaded.SetPreserveSpaceValues(tt.preserve)
+			if err := reloaded.LoadFromString(p.ToString()); err != nil {
+				t.Fatalf("reload: %v", err)
+			}
+			if got, _ := reloaded.Get("s", "k"); got != tt.want {
+				t.Errorf("after round trip: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	p := loadSample(t)
+
+	if err := p.Update("server", "port", "9090"); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got, _ := p.Get("server", "port"); got != "9090" {
+		t.Errorf("got %q, want %q", got, "9090")
+	}
+
+	if err := p.Update("server", "prot", "1"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("missing key: got %v, want %v", err, ErrKeyNotFound)
+	}
+	if p.HasKey("server", "prot") {
+		t.Error("Update created a missing key")
+	}
+}
+
+func TestGetRaw(t *testing.T) {
+	p := NewParser()
+	p.SetUnquoteValues(true)
+	if err := p.LoadFromString(`[s]
+text = "line1\nsaid \"hi\""
+plain = as is
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := p.Get("s", "text"); got != "line1\nsaid \"hi\"" {
+		t.Errorf("Get = %q", got)
+	}
+	if got, ok := p.GetRaw("s", "text"); !ok || got != `"line1\nsaid \"hi\""` {
+		t.Errorf("GetRaw = (%q, %v)", got, ok)
+	}
+	if got, ok := p.GetRaw("s", "plain"); !ok || got != "as is" {
+		t.Errorf("GetRaw(plain) = (%q, %v)", got, ok)
+	}
+
+	if err := p.Set("s", "text", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := p.GetRaw("s", "text"); got != "new" {
+		t.Errorf("GetRaw after Set = %q, want %q", got, "new")
+	}
+	if _, ok := p.GetRaw("s", "missing"); ok {
+		t.Error("GetRaw(missing) reported ok")
+	}
+}
+
+func TestToStringGlobalOrder(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("zeta = 1\nalpha = 2\nmid = 3\n[s]\nkey = value\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Set("", "beta", "4"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Set("", "zeta", "5"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "zeta = 5\nalpha = 2\nmid = 3\nbeta = 4\n\n[s]\nkey = value\n"
+	for i := 0; i < 5; i++ {
+		if got := p.ToString(); got != want {
+			t.Fatalf("ToString() = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestEmptySectionName(t *testing.T) {
+	const input = "[server]\nhost = localhost\n[]\nname = demo\n"
+
+	t.Run("rejected", func(t *testing.T) {
+		err := NewParser().LoadFromString(input)
+		if !errors.Is(err, ErrEmptySectionName) || err.Error() != "line 3: empty section name" {
+			t.Errorf("got %v, want %q", err, "line 3: empty section name")
+		}
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		p := NewParser()
+		p.SetAllowEmptySectionName(true)
+		if err := p.LoadFromString(input); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := p.GetSectionNames(), []string{"server"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("GetSectionNames() = %v, want %v", got, want)
+		}
+		if got, want := p.GetGlobalKeys(), map[string]string{"name": "demo"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("GetGlobalKeys() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestGetSectionOrdered(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("b = 2\na = 1\n[s]\nzeta = z\nalpha = a\nmid = m\n[empty]\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		section string
+		want    []KeyValue
+	}{
+		{"s", []KeyValue{{"zeta", "z"}, {"alpha", "a"}, {"mid", "m"}}},
+		{"", []KeyValue{{"b", "2"}, {"a", "1"}}},
+		{"empty", []KeyValue{}},
+	}
+	for _, tt := range tests {
+		got, ok := p.GetSectionOrdered(tt.section)
+		if !ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetSectionOrdered(%q) = (%v, %v), want %v", tt.section, got, ok, tt.want)
+		}
+	}
+
+	if _, ok := p.GetSectionOrdered("missing"); ok {
+		t.Error("GetSectionOrdered(missing) reported ok")
+	}
+}
+
+func TestSetValueTransformer(t *testing.T) {
+	p := NewParser()
+	var seen []string
+	p.SetValueTransformer(func(section, key, value string) string {
+		seen = append(seen, section+"/"+key)
+		return strings.ToUpper(value)
+	})
+	if err := p.LoadFromString(sampleINI); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := p.Get("server", "host"); got != "LOCALHOST" {
+		t.Errorf("server.host = %q, want %q", got, "LOCALHOST")
+	}
+	if got, _ := p.Get("", "name"); got != "DEMO" {
+		t.Errorf("name = %q, want %q", got, "DEMO")
+	}
+	if got, _ := p.GetRaw("server", "host"); got != "localhost" {
+		t.Errorf("GetRaw(server, host) = %q, want %q", got, "localhost")
+	}
+	want := []string{"/name", "server/host", "server/port", "database/user", "database/password"}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("transformer called for %v, want %v", seen, want)
+	}
+
+	p = NewParser()
+	p.SetValueTransformer(nil)
+	if err := p.LoadFromString(sampleINI); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := p.Get("server", "host"); got != "localhost" {
+		t.Errorf("nil transformer: server.host = %q, want %q", got, "localhost")
+	}
+}
+
+func TestGetGlobalKeysOrdered(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("zeta = 1\nalpha = 2\nmid = 3\n[s]\nkey = value\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []KeyValue{{"zeta", "1"}, {"alpha", "2"}, {"mid", "3"}}
+	if got := p.GetGlobalKeysOrdered(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetGlobalKeysOrdered() = %v, want %v", got, want)
+	}
+	if got := NewParser().GetGlobalKeysOrdered(); len(got) != 0 {
+		t.Errorf("empty parser: got %v, want none", got)
+	}
+}
+
+func TestSection(t *testing.T) {
+	p := loadSample(t)
+
+	got := p.Section("server")
+	if want := map[string]string{"host": "localhost", "port": "8080"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Section(server) = %v, want %v", got, want)
+	}
+	got["host"] = "changed"
+	if host, _ := p.Get("server", "host"); host != "localhost" {
+		t.Errorf("modifying the result changed the parser: host = %q", host)
+	}
+
+	missing := p.Section("missing")
+	if missing == nil || len(missing) != 0 {
+		t.Fatalf("Section(missing) = %#v, want an empty map", missing)
+	}
+	missing["key"] = "value"
+	if p.HasSection("missing") {
+		t.Error("writing to the result created the section")
+	}
+}
+
+func TestSetTOC(t *testing.T) {
+	p := loadSample(t)
+	p.SetTOC(true)
+
+	want := "; Sections:\n;   server\n;   database\n\nname = demo\n\n[server]\n"
+	if got := p.ToString(); !strings.HasPrefix(got, want) {
+		t.Errorf("ToString() = %q, want prefix %q", got, want)
+	}
+
+	if err := p.InsertSection("cache", 0); err != nil {
+		t.Fatal(err)
+	}
+	p.SetCommentPrefixes("#")
+	want = "# Sections:\n#   cache\n#   server\n#   database\n\n"
+	if got := p.ToString(); !strings.HasPrefix(got, want) {
+		t.Errorf("after InsertSection: ToString() = %q, want prefix %q", got, want)
+	}
+
+	empty := NewParser()
+	empty.SetTOC(true)
+	if got := empty.ToString(); got != "" {
+		t.Errorf("empty parser: ToString() = %q, want \"\"", got)
+	}
+}
+
+func TestSetTOCRoundTrip(t *testing.T) {
+	for _, input := range []string{sampleINI, "[server]\nhost = localhost\n"} {
+		p := NewParser()
+		p.SetTOC(true)
+		if err := p.LoadFromString(input); err != nil {
+			t.Fatal(err)
+		}
+		out := p.ToString()
+
+		q := NewParser()
+		q.SetTOC(true)
+		if err := q.LoadFromString(out); err != nil {
+			t.Fatalf("LoadFromString(%q): %v", out, err)
+		}
+		if got := q.GetComments("server"); len(got) != 0 {
+			t.Errorf("GetComments(server) = %q, want none", got)
+		}
+		if got := q.GetComments(""); len(got) != 0 {
+			t.Errorf("GetComments(\"\") = %q, want none", got)
+		}
+		if got := q.ToString(); got != out {
+			t.Errorf("round trip: got %q, want %q", got, out)
+		}
+	}
+
+	p := NewParser()
+	if err := p.LoadFromString("; Sections:\n;   server\n\n; the server\n[server]\nhost = localhost\n"); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.GetComments("server"); !reflect.DeepEqual(got, []string{"the server"}) {
+		t.Errorf("GetComments(server) = %q, want [the server]", got)
+	}
+}
+
+func TestInvalidSectionHeader(t *testing.T) {
+	const input = "[server]\nhost = localhost\n[unclosed\nport = 8080\n[database]\nuser = admin\n"
+
+	err := NewParser().LoadFromString(input)
+	if !errors.Is(err, ErrInvalidSectionHeader) || !strings.HasPrefix(err.Error(), "line 3: ") {
+		t.Fatalf("got %v, want %v on line 3", err, ErrInvalidSectionHeader)
+	}
+
+	p := NewParser()
+	p.SetSkipErrors(true)
+	if err := p.LoadFromString(input); err != nil {
+		t.Fatalf("LoadFromString: %v", err)
+	}
+	if errs := p.Errors(); len(errs) != 1 || !errors.Is(errs[0], ErrInvalidSectionHeader) {
+		t.Errorf("Errors() = %v, want one %v", errs, ErrInvalidSectionHeader)
+	}
+	want := map[string]map[string]string{
+		"server":   {"host": "localhost", "port": "8080"},
+		"database": {"user": "admin"},
+	}
+	if got := p.GetSections(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSections() = %v, want %v", got, want)
+	}
+
+	p = NewParser()
+	p.SetSkipErrors(true)
+	if err := p.LoadFromString("[broken\nkey = value\n"); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.GetGlobalKeys(); !reflect.DeepEqual(got, map[string]string{"key": "value"}) {
+		t.Errorf("GetGlobalKeys() = %v, want the key kept as a global", got)
+	}
+}
+
+func TestToStringOrdered(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("name = demo\n[a]\nk = 1\n[b]\nk = 2\n[c]\nk = 3\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		order []string
+		want  string
+	}{
+		{[]string{"c", "a", "b"}, "name = demo\n\n[c]\nk = 3\n\n[a]\nk = 1\n\n[b]\nk = 2\n"},
+		{[]string{"b", "missing", "b"}, "name = demo\n\n[b]\nk = 2\n\n[a]\nk = 1\n\n[c]\nk = 3\n"},
+		{nil, p.ToString()},
+	}
+	for _, tt := range tests {
+		if got := p.ToStringOrdered(tt.order); got != tt.want {
+			t.Errorf("ToStringOrdered(%v) = %q, want %q", tt.order, got, tt.want)
+		}
+	}
+	if got, want := p.GetSectionNames(), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("section order changed to %v", got)
+	}
+}
+
+func TestSectionNameBackslashes(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("[C:\\dir\\sub]\nkey = value\n[a\\]b\\\\c]\nkey = value\n"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := p.GetSectionNames(), []string{`C:\dir\sub`, `a]b\c`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestToStringOrderedTOC(t *testing.T) {
+	p := loadSample(t)
+	p.SetTOC(true)
+
+	want := "; Sections:\n;   database\n;   server\n\nname = demo\n\n[database]\n"
+	if got := p.ToStringOrdered([]string{"database"}); !strings.HasPrefix(got, want) {
+		t.Errorf("ToStringOrdered() = %q, want prefix %q", got, want)
+	}
+}
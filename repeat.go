@@ -0,0 +1,52 @@
+package iniparser
+
+// SetRepeatableSections declares sections whose header may appear several
+// times to describe a list of similar items, such as one [server] block per
+// server. Each occurrence of such a section starts a new block, available
+// from GetRepeated. The blocks are still merged into a single section as
+// for any other section, so Get returns the value from the last block
+// setting a key, and repeating the header is not reported by Conflicts.
+// Calling it again replaces the previous list.
+func (p *Parser) SetRepeatableSections(names ...string) {
+	p.repeatable = make(map[string]bool, len(names))
+	for _, name := range names {
+		p.repeatable[name] = true
+	}
+}
+
+// GetRepeated returns a copy of each block of the repeatable section, in
+// source order, holding the keys parsed inside that block. It returns nil
+// for a section that was not declared with SetRepeatableSections or not
+// parsed. Later changes made with Set and similar methods are not reflected.
+func (p *Parser) GetRepeated(section string) []map[string]string {
+	blocks := p.repeated[section]
+	if blocks == nil {
+		return nil
+	}
+	copies := make([]map[string]string, len(blocks))
+	for i, block := range blocks {
+		copies[i] = copyMap(block)
+	}
+	return copies
+}
+
+// startBlock opens a new block when section is repeatable.
+func (p *Parser) startBlock(section string) {
+	if !p.repeatable[section] {
+		return
+	}
+	if p.repeated == nil {
+		p.repeated = make(map[string][]map[string]string)
+	}
+	p.repeated[section] = append(p.repeated[section], make(map[string]string))
+}
+
+// currentBlock returns the block being parsed in section, if it is
+// repeatable.
+func (p *Parser) currentBlock(section string) (map[string]string, bool) {
+	blocks := p.repeated[section]
+	if !p.repeatable[section] || len(blocks) == 0 {
+		return nil, false
+	}
+	return blocks[len(blocks)-1], true
+}
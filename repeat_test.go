@@ -0,0 +1,52 @@
+package iniparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRepeatableSections(t *testing.T) {
+	p := NewParser()
+	p.SetRepeatableSections("server")
+	err := p.LoadFromString(`[server]
+host = a.example.com
+port = 80
+[server]
+host = b.example.com
+[database]
+user = admin
+[server]
+host = c.example.com
+port = 8080
+[database]
+password = secret
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []map[string]string{
+		{"host": "a.example.com", "port": "80"},
+		{"host": "b.example.com"},
+		{"host": "c.example.com", "port": "8080"},
+	}
+	if got := p.GetRepeated("server"); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetRepeated(server) = %v, want %v", got, want)
+	}
+	if got := p.GetRepeated("database"); got != nil {
+		t.Errorf("GetRepeated(database) = %v, want nil", got)
+	}
+
+	merged := map[string]string{"user": "admin", "password": "secret"}
+	if got := p.GetSections()["database"]; !reflect.DeepEqual(got, merged) {
+		t.Errorf("database = %v, want merged %v", got, merged)
+	}
+	if got, _ := p.Get("server", "host"); got != "c.example.com" {
+		t.Errorf("server.host = %q, want the last block's value", got)
+	}
+
+	wantConflicts := []string{"line 11: duplicate section [database] merged with the earlier one"}
+	if got := p.Conflicts(); !reflect.DeepEqual(got, wantConflicts) {
+		t.Errorf("Conflicts() = %q, want %q", got, wantConflicts)
+	}
+}
@@ -0,0 +1,248 @@
+package iniparser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrSectionExists is returned when creating a section that already
+	// exists.
+	ErrSectionExists = errors.New("section already exists")
+	// ErrSectionNotFound is returned when a requested section does not exist.
+	ErrSectionNotFound = errors.New("section not found")
+	// ErrKeyCollision is returned when renaming keys maps two keys to the
+	// same name.
+	ErrKeyCollision = errors.New("key collision")
+	// ErrIndexOutOfRange is returned for a section position outside the
+	// section list.
+	ErrIndexOutOfRange = errors.New("index out of range")
+)
+
+// MatchSections returns the section names matching the regular expression
+// pattern, in section order. It fails if pattern does not compile.
+func (p *Parser) MatchSections(pattern string) ([]string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	for _, section := range p.sections {
+		if re.MatchString(section) {
+			matches = append(matches, section)
+		}
+	}
+	return matches, nil
+}
+
+// InsertSection creates an empty section and places it at position atIndex
+// of the section order, where len(GetSectionNames()) appends it. It fails if
+// the section name is empty, the section already exists or atIndex is out of
+// range, and with ErrUnexpectedSection in flat mode.
+func (p *Parser) InsertSection(section string, atIndex int) error {
+	if section == "" {
+		return ErrEmptySectionName
+	}
+	if err := checkName(section); err != nil {
+		return err
+	}
+	if p.flat {
+		return fmt.Errorf("%w: %s", ErrUnexpectedSection, section)
+	}
+	if p.HasSection(section) {
+		return fmt.Errorf("%w: %s", ErrSectionExists, section)
+	}
+	if atIndex < 0 || atIndex > len(p.sections) {
+		return fmt.Errorf("%w: %d not in [0, %d]", ErrIndexOutOfRange, atIndex, len(p.sections))
+	}
+
+	p.createSectionIfNotExist(section)
+	copy(p.sections[atIndex+1:], p.sections[atIndex:])
+	p.sections[atIndex] = section
+	p.dirty = true
+	return nil
+}
+
+// TransformKeys renames every key of section to fn(key), for example with
+// strings.ToUpper, keeping values and key order. If two keys would end up
+// with the same name, or a new name is invalid, it fails with the section
+// left unchanged. An empty section refers to the global keys.
+func (p *Parser) TransformKeys(section string, fn func(string) string) error {
+	keys, ok := p.sectionKeys(section)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrSectionNotFound, section)
+	}
+
+	names := make(map[string]string, len(keys))
+	sources := make(map[string]string, len(keys))
+	for _, key := range sortedKeys(keys) {
+		name := fn(key)
+		if name == "" {
+			return fmt.Errorf("%w: %q renamed to an empty key", ErrEmptyKey, key)
+		}
+		if err := checkName(name); err != nil {
+			return err
+		}
+		if other, dup := sources[name]; dup {
+			return fmt.Errorf("%w: %q and %q both become %q", ErrKeyCollision, other, key, name)
+		}
+		names[key] = name
+		sources[name] = key
+	}
+
+	resolved := p.resolveSection(section)
+	if resolved == "" {
+		p.globalKeys = renameKeys(keys, names)
+		for i, key := range p.globalOrder {
+			p.globalOrder[i] = names[key]
+		}
+	} else {
+		p.data[resolved] = renameKeys(keys, names)
+		for i, key := range p.keyOrder[resolved] {
+			p.keyOrder[resolved][i] = names[key]
+		}
+	}
+	if lines, ok := p.keyLines[resolved]; ok {
+		p.keyLines[resolved] = renameKeys(lines, names)
+	}
+	if values, ok := p.multiValues[resolved]; ok {
+		p.multiValues[resolved] = renameKeys(values, names)
+	}
+	if raw, ok := p.rawValues[resolved]; ok {
+		p.rawValues[resolved] = renameKeys(raw, names)
+	}
+	if defaults, ok := p.defaults[resolved]; ok {
+		p.defaults[resolved] = renameKeys(defaults, names)
+	}
+	p.dirty = true
+	return nil
+}
+
+// renameKeys returns a copy of m with every key renamed through names.
+func renameKeys[V any](m map[string]V, names map[string]string) map[string]V {
+	renamed := make(map[string]V, len(m))
+	for key, v := range m {
+		renamed[names[key]] = v
+	}
+	return renamed
+}
+
+// SectionSizes returns the number of keys in each section. Global keys are
+// counted under the empty section name when there are any.
+func (p *Parser) SectionSizes() map[string]int {
+	sizes := make(map[string]int, len(p.data)+1)
+	if len(p.globalKeys) > 0 {
+		sizes[""] = len(p.globalKeys)
+	}
+	for section, keys := range p.data {
+		sizes[section] = len(keys)
+	}
+	return sizes
+}
+
+// TotalKeys returns the number of keys across all sections, including the
+// global keys.
+func (p *Parser) TotalKeys() int {
+	total := len(p.globalKeys)
+	for _, keys := range p.data {
+		total += len(keys)
+	}
+	return total
+}
+
+// WalkSections calls fn for each section in order with a copy of its keys,
+// stopping as soon as fn returns false. Global keys are not visited.
+func (p *Parser) WalkSections(fn func(section string, kv map[string]string) bool) {
+	for _, section := range p.sections {
+		if !fn(section, copyMap(p.data[section])) {
+			return
+		}
+	}
+}
+
+// RequireSections checks that every named section exists, even if empty. The
+// returned ErrSectionNotFound error lists all missing sections at once.
+func (p *Parser) RequireSections(names ...string) error {
+	var missing []string
+	for _, name := range names {
+		if !p.HasSection(name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrSectionNotFound, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// SectionsWithKey returns, in section order, the sections that define key.
+func (p *Parser) SectionsWithKey(key string) []string {
+	var sections []string
+	for _, section := range p.sections {
+		if _, ok := p.data[section][key]; ok {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+// EmptySections returns, in section order, the sections that have no keys.
+func (p *Parser) EmptySections() []string {
+	var empty []string
+	for _, section := range p.sections {
+		if len(p.data[section]) == 0 {
+			empty = append(empty, section)
+		}
+	}
+	return empty
+}
+
+// PruneEmptySections removes the sections that have no keys, along with
+// their comments, and returns how many were removed.
+func (p *Parser) PruneEmptySections() int {
+	kept := p.sections[:0]
+	removed := 0
+	for _, section := range p.sections {
+		if len(p.data[section]) > 0 {
+			kept = append(kept, section)
+			continue
+		}
+		delete(p.data, section)
+		delete(p.keyOrder, section)
+		delete(p.comments, section)
+		delete(p.keyLines, section)
+		delete(p.multiValues, section)
+		delete(p.rawValues, section)
+		delete(p.defaults, section)
+		removed++
+	}
+	p.sections = kept
+	if removed > 0 {
+		p.dirty = true
+	}
+	return removed
+}
+
+// SectionKeyDiff compares the keys of sections a and b. It returns the keys
+// only in a and the keys common to both in the order of a, and the keys only
+// in b in the order of b. A missing section has no keys.
+func (p *Parser) SectionKeyDiff(a, b string) (onlyA, onlyB, common []string) {
+	keysA, _ := p.sectionKeys(a)
+	keysB, _ := p.sectionKeys(b)
+	for _, key := range p.keyOrderOf(a) {
+		if _, ok := keysB[key]; ok {
+			common = append(common, key)
+		} else {
+			onlyA = append(onlyA, key)
+		}
+	}
+	for _, key := range p.keyOrderOf(b) {
+		if _, ok := keysA[key]; !ok {
+			onlyB = append(onlyB, key)
+		}
+	}
+	return onlyA, onlyB, common
+}
@@ -0,0 +1,263 @@
+package iniparser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchSections(t *testing.T) {
+	p := NewParser()
+	err := p.LoadFromString("[server.1]\nk = v\n[database]\nk = v\n[server.2]\nk = v\n[server.x]\nk = v\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := p.MatchSections(`^server\.\d+$`)
+	if err != nil {
+		t.Fatalf("MatchSections: %v", err)
+	}
+	if want := []string{"server.1", "server.2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := p.MatchSections(`server(`); err == nil {
+		t.Error("invalid pattern: got nil error")
+	}
+}
+
+func TestInsertSection(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("[b]\nk = v\n[d]\nk = v\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.InsertSection("a", 0); err != nil {
+		t.Fatalf("front: %v", err)
+	}
+	if err := p.InsertSection("c", 2); err != nil {
+		t.Fatalf("middle: %v", err)
+	}
+	if err := p.InsertSection("e", 4); err != nil {
+		t.Fatalf("end: %v", err)
+	}
+	if want := []string{"a", "b", "c", "d", "e"}; !reflect.DeepEqual(p.GetSectionNames(), want) {
+		t.Errorf("got %v, want %v", p.GetSectionNames(), want)
+	}
+
+	if err := p.InsertSection("f", 6); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("out of range: got %v, want %v", err, ErrIndexOutOfRange)
+	}
+	if err := p.InsertSection("f", -1); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("negative: got %v, want %v", err, ErrIndexOutOfRange)
+	}
+	if err := p.InsertSection("b", 0); !errors.Is(err, ErrSectionExists) {
+		t.Errorf("existing: got %v, want %v", err, ErrSectionExists)
+	}
+	if err := p.InsertSection("", 0); !errors.Is(err, ErrEmptySectionName) {
+		t.Errorf("empty name: got %v, want %v", err, ErrEmptySectionName)
+	}
+	if p.HasSection("f") || p.HasSection("") {
+		t.Error("failed insert created the section")
+	}
+}
+
+func TestTransformKeys(t *testing.T) {
+	t.Run("uppercase", func(t *testing.T) {
+		p := loadSample(t)
+		if err := p.TransformKeys("server", strings.ToUpper); err != nil {
+			t.Fatalf("TransformKeys: %v", err)
+		}
+
+		want := []Entry{{"server", "HOST", "localhost"}, {"server", "PORT", "8080"}}
+		var got []Entry
+		for _, e := range p.Entries() {
+			if e.Section == "server" {
+				got = append(got, e)
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if line, ok := p.KeyLine("server", "PORT"); !ok || line != 6 {
+			t.Errorf("KeyLine(PORT) = (%d, %v), want (6, true)", line, ok)
+		}
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		p := NewParser()
+		if err := p.LoadFromString("[s]\nName = a\nNAME = b\n"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := p.TransformKeys("s", strings.ToLower); !errors.Is(err, ErrKeyCollision) {
+			t.Fatalf("got %v, want %v", err, ErrKeyCollision)
+		}
+		if want := (map[string]map[string]string{"s": {"Name": "a", "NAME": "b"}}); !reflect.DeepEqual(p.GetSections(), want) {
+			t.Errorf("section changed: %v", p.GetSections())
+		}
+	})
+
+	t.Run("missing section", func(t *testing.T) {
+		if err := NewParser().TransformKeys("s", strings.ToUpper); !errors.Is(err, ErrSectionNotFound) {
+			t.Errorf("got %v, want %v", err, ErrSectionNotFound)
+		}
+	})
+}
+
+func TestSectionSizes(t *testing.T) {
+	p := loadSample(t)
+	if err := p.InsertSection("empty", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Set("database", "host", "db"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int{"": 1, "empty": 0, "server": 2, "database": 3}
+	if got := p.SectionSizes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestTotalKeys(t *testing.T) {
+	globals := NewParser()
+	if err := globals.LoadFromString("a = 1\nb = 2\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		p    *Parser
+		want int
+	}{
+		{"empty", NewParser(), 0},
+		{"globals only", globals, 2},
+		{"sections", loadSample(t), 5},
+	}
+	for _, tt := range tests {
+		if got := tt.p.TotalKeys(); got != tt.want {
+			t.Errorf("%s: TotalKeys() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWalkSections(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("[a]\nk = 1\n[b]\nk = 2\n[c]\nk = 3\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	var visited []string
+	p.WalkSections(func(section string, kv map[string]string) bool {
+		visited = append(visited, section+"="+kv["k"])
+		return true
+	})
+	if want := []string{"a=1", "b=2", "c=3"}; !reflect.DeepEqual(visited, want) {
+		t.Errorf("got %v, want %v", visited, want)
+	}
+
+	visited = nil
+	p.WalkSections(func(section string, kv map[string]string) bool {
+		visited = append(visited, section)
+		return section != "b"
+	})
+	if want := []string{"a", "b"}; !reflect.DeepEqual(visited, want) {
+		t.Errorf("early exit: got %v, want %v", visited, want)
+	}
+}
+
+func TestRequireSections(t *testing.T) {
+	p := loadSample(t)
+	if err := p.InsertSection("plugins", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.RequireSections("server", "plugins"); err != nil {
+		t.Errorf("all present: got %v", err)
+	}
+
+	err := p.RequireSections("server", "cache", "plugins", "auth")
+	if !errors.Is(err, ErrSectionNotFound) {
+		t.Fatalf("got %v, want %v", err, ErrSectionNotFound)
+	}
+	if !strings.HasSuffix(err.Error(), ": cache, auth") {
+		t.Errorf("error %q does not list the missing sections", err)
+	}
+}
+
+func TestSectionsWithKey(t *testing.T) {
+	p := NewParser()
+	err := p.LoadFromString("[api]\nlog_level = info\n[db]\nhost = x\n[worker]\nlog_level = debug\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := p.SectionsWithKey("log_level"), []string{"api", "worker"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := p.SectionsWithKey("timeout"); len(got) != 0 {
+		t.Errorf("got %v, want none", got)
+	}
+}
+
+func TestPruneEmptySections(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("[empty]\n[full]\nkey = value\n[also]\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := p.EmptySections(), []string{"empty", "also"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("EmptySections() = %v, want %v", got, want)
+	}
+	if got := p.PruneEmptySections(); got != 2 {
+		t.Errorf("PruneEmptySections() = %d, want 2", got)
+	}
+	if got, want := p.GetSectionNames(), []string{"full"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSectionNames() = %v, want %v", got, want)
+	}
+	if p.HasSection("empty") {
+		t.Error("HasSection(empty) = true after pruning")
+	}
+	if got := p.EmptySections(); got != nil {
+		t.Errorf("EmptySections() after pruning = %v, want nil", got)
+	}
+	if got := p.PruneEmptySections(); got != 0 {
+		t.Errorf("second PruneEmptySections() = %d, want 0", got)
+	}
+}
+
+func TestSectionKeyDiff(t *testing.T) {
+	p := NewParser()
+	err := p.LoadFromString(`[prod]
+host = prod.example.com
+replicas = 3
+debug = false
+[staging]
+debug = true
+host = staging.example.com
+seed = 42
+[other]
+color = blue
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		a, b                 string
+		onlyA, onlyB, common []string
+	}{
+		{"prod", "staging", []string{"replicas"}, []string{"seed"}, []string{"host", "debug"}},
+		{"prod", "other", []string{"host", "replicas", "debug"}, []string{"color"}, nil},
+		{"other", "missing", []string{"color"}, nil, nil},
+	}
+	for _, tt := range tests {
+		onlyA, onlyB, common := p.SectionKeyDiff(tt.a, tt.b)
+		if !reflect.DeepEqual(onlyA, tt.onlyA) || !reflect.DeepEqual(onlyB, tt.onlyB) || !reflect.DeepEqual(common, tt.common) {
+			t.Errorf("SectionKeyDiff(%s, %s) = (%v, %v, %v), want (%v, %v, %v)",
+				tt.a, tt.b, onlyA, onlyB, common, tt.onlyA, tt.onlyB, tt.common)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package iniparser
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrUnknownSnapshot is returned by Rollback for a token that Snapshot did
+// not return.
+var ErrUnknownSnapshot = errors.New("unknown snapshot")
+
+// snapshot is a deep copy of the parsed content of a Parser.
+type snapshot struct {
+	sections    []string
+	data        map[string]map[string]string
+	keyOrder    map[string][]string
+	globalKeys  map[string]string
+	globalOrder []string
+	comments    map[string][]string
+	keyLines    map[string]map[string]int
+
+	multiValues map[string]map[string][]string
+	rawValues   map[string]map[string]string
+	defaults    map[string]map[string]bool
+	repeated    map[string][]map[string]string
+}
+
+// Snapshot records the current sections, keys and values and returns a token
+// that Rollback accepts to restore them. Settings such as delimiters or
+// validators are not part of a snapshot.
+func (p *Parser) Snapshot() int {
+	if p.snapshots == nil {
+		p.snapshots = make(map[int]*snapshot)
+	}
+	p.nextSnapshot++
+	p.snapshots[p.nextSnapshot] = &snapshot{
+		sections:    append([]string(nil), p.sections...),
+		data:        copyNested(p.data),
+		keyOrder:    copySlices(p.keyOrder),
+		globalKeys:  copyMap(p.globalKeys),
+		globalOrder: slices.Clone(p.globalOrder),
+		comments:    copySlices(p.comments),
+		keyLines:    copyNested(p.keyLines),
+
+		multiValues: copyMultiValues(p.multiValues),
+		rawValues:   copyNested(p.rawValues),
+		defaults:    copyNested(p.defaults),
+		repeated:    copyRepeated(p.repeated),
+	}
+	return p.nextSnapshot
+}
+
+// Rollback restores the content recorded by the Snapshot that returned
+// token. The snapshot stays available, so the same token can be used again.
+func (p *Parser) Rollback(token int) error {
+	s, ok := p.snapshots[token]
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrUnknownSnapshot, token)
+	}
+
+	p.sections = append([]string(nil), s.sections...)
+	p.data = copyNested(s.data)
+	p.keyOrder = copySlices(s.keyOrder)
+	p.globalKeys = copyMap(s.globalKeys)
+	p.globalOrder = slices.Clone(s.globalOrder)
+	p.comments = copySlices(s.comments)
+	p.keyLines = copyNested(s.keyLines)
+	p.multiValues = copyMultiValues(s.multiValues)
+	p.rawValues = copyNested(s.rawValues)
+	p.defaults = copyNested(s.defaults)
+	p.repeated = copyRepeated(s.repeated)
+	p.dirty = true
+	return nil
+}
+
+func copyNested[V any](m map[string]map[string]V) map[string]map[string]V {
+	c := make(map[string]map[string]V, len(m))
+	for k, inner := range m {
+		ic := make(map[string]V, len(inner))
+		for ik, v := range inner {
+			ic[ik] = v
+		}
+		c[k] = ic
+	}
+	return c
+}
+
+func copyMultiValues(m map[string]map[string][]string) map[string]map[string][]string {
+	c := make(map[string]map[string][]string, len(m))
+	for section, keys := range m {
+		c[section] = copySlices(keys)
+	}
+	return c
+}
+
+func copyRepeated(m map[string][]map[string]string) map[string][]map[string]string {
+	c := make(map[string][]map[string]string, len(m))
+	for section, blocks := range m {
+		copies := make([]map[string]string, len(blocks))
+		for i, block := range blocks {
+			copies[i] = copyMap(block)
+		}
+		c[section] = copies
+	}
+	return c
+}
+
+func copySlices[V any](m map[string][]V) map[string][]V {
+	c := make(map[string][]V, len(m))
+	for k, s := range m {
+		c[k] = append([]V(nil), s...)
+	}
+	return c
+}
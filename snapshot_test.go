@@ -0,0 +1,58 @@
+package iniparser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshotRollback(t *testing.T) {
+	p := loadSample(t)
+	before := p.ToString()
+
+	token := p.Snapshot()
+	if err := p.Set("server", "port", "9090"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Set("cache", "size", "64"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Set("", "name", "changed"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Rollback(token); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if got := p.ToString(); got != before {
+		t.Errorf("got:\n%s\nwant:\n%s", got, before)
+	}
+	if p.HasSection("cache") {
+		t.Error("section added after the snapshot still exists")
+	}
+
+	if err := p.Rollback(token + 1); !errors.Is(err, ErrUnknownSnapshot) {
+		t.Errorf("got %v, want %v", err, ErrUnknownSnapshot)
+	}
+}
+
+func TestRollbackRepeatedSections(t *testing.T) {
+	p := NewParser()
+	p.SetRepeatableSections("server")
+	if err := p.LoadFromString("[server]\nhost = a\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	token := p.Snapshot()
+	if err := p.LoadFromString("[server]\nhost = b\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Rollback(token); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+
+	want := []map[string]string{{"host": "a"}}
+	if got := p.GetRepeated("server"); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetRepeated = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,119 @@
+package iniparser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// defaultKeyDelimiter separates keys from values unless a section overrides
+// it.
+const defaultKeyDelimiter = "="
+
+// ErrDelimiterSpacing is returned for a key/value line whose spacing around
+// the delimiter breaks the style set by SetRequireDelimiterSpacing.
+var ErrDelimiterSpacing = errors.New("wrong spacing around delimiter")
+
+// DelimiterSpacing is a style of spacing around the key/value delimiter.
+type DelimiterSpacing int
+
+const (
+	// DelimiterSpacingAny accepts any spacing; it is the default.
+	DelimiterSpacingAny DelimiterSpacing = iota
+	// DelimiterSpacingNone requires key=value, with no whitespace next to
+	// the delimiter.
+	DelimiterSpacingNone
+	// DelimiterSpacingAround requires key = value, with whitespace on both
+	// sides of the delimiter. Only the space before it is required when the
+	// value is empty.
+	DelimiterSpacingAround
+)
+
+// SetRequireDelimiterSpacing makes parsing reject key/value lines that do
+// not follow style with ErrDelimiterSpacing. Writing follows the style too:
+// DelimiterSpacingNone writes key=value, the others key = value.
+func (p *Parser) SetRequireDelimiterSpacing(style DelimiterSpacing) {
+	p.spacing = style
+}
+
+// checkSpacing checks the text on each side of the delimiter of a key/value
+// line against the spacing style.
+func (p *Parser) checkSpacing(before, after string) error {
+	spaceBefore := strings.TrimRight(before, " \t") != before
+	spaceAfter := strings.TrimLeft(after, " \t") != after
+	switch p.spacing {
+	case DelimiterSpacingNone:
+		if spaceBefore || spaceAfter {
+			return fmt.Errorf("%w: want key=value", ErrDelimiterSpacing)
+		}
+	case DelimiterSpacingAround:
+		if !spaceBefore || !spaceAfter && after != "" {
+			return fmt.Errorf("%w: want key = value", ErrDelimiterSpacing)
+		}
+	}
+	return nil
+}
+
+// sectionSyntax holds the per-section overrides set by SetSectionDelimiter
+// and SetSectionCommentPrefixes.
+type sectionSyntax struct {
+	delimiter       string
+	commentPrefixes []string
+}
+
+// SetSectionDelimiter makes lines inside section split keys from values on
+// delim instead of "=", both when parsing and writing, so that a file can
+// mix conventions such as [weird] using "key: value". The override applies
+// from the section header on and only while that section is current; other
+// sections keep "=". An empty delim restores the default.
+func (p *Parser) SetSectionDelimiter(section, delim string) {
+	s := p.syntax[section]
+	s.delimiter = delim
+	p.setSectionSyntax(section, s)
+}
+
+// SetSectionCommentPrefixes replaces the comment prefixes recognized inside
+// section, as SetCommentPrefixes does for the whole file. Calling it with no
+// prefixes restores the parser-wide ones for that section.
+func (p *Parser) SetSectionCommentPrefixes(section string, prefixes ...string) {
+	s := p.syntax[section]
+	s.commentPrefixes = append([]string(nil), prefixes...)
+	p.setSectionSyntax(section, s)
+}
+
+func (p *Parser) setSectionSyntax(section string, s sectionSyntax) {
+	if s.delimiter == "" && len(s.commentPrefixes) == 0 {
+		delete(p.syntax, section)
+		return
+	}
+	if p.syntax == nil {
+		p.syntax = make(map[string]sectionSyntax)
+	}
+	p.syntax[section] = s
+}
+
+// keyDelimiter returns the key-value delimiter used inside section.
+func (p *Parser) keyDelimiter(section string) string {
+	if delim := p.syntax[section].delimiter; delim != "" {
+		return delim
+	}
+	return defaultKeyDelimiter
+}
+
+// sectionCommentPrefix is like commentPrefix but honours the prefixes set
+// for section.
+func (p *Parser) sectionCommentPrefix(section, line string) (string, bool) {
+	return matchCommentPrefix(line, p.sectionCommentPrefixes(section))
+}
+
+// sectionCommentPrefixes returns the comment prefixes in effect inside
+// section.
+func (p *Parser) sectionCommentPrefixes(section string) []string {
+	if prefixes := p.syntax[section].commentPrefixes; len(prefixes) > 0 {
+		return prefixes
+	}
+	if len(p.commentPrefixes) > 0 {
+		return p.commentPrefixes
+	}
+	return defaultCommentPrefixes
+}
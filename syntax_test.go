@@ -0,0 +1,136 @@
+package iniparser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSectionDelimiter(t *testing.T) {
+	const input = `name = demo
+
+[weird]
+// uses colons
+url: http://example.com/?a=b
+mode: fast
+
+[normal]
+// not a comment here
+url = http://example.com/
+`
+	p := NewParser()
+	p.SetSectionDelimiter("weird", ":")
+	p.SetSectionCommentPrefixes("weird", "//")
+
+	err := p.LoadFromString(input)
+	if err == nil {
+		t.Fatal("expected an error for the // line outside [weird]")
+	}
+	if want := "line 9: missing key/value delimiter"; err.Error() != want {
+		t.Fatalf("error = %q, want %q", err, want)
+	}
+
+	p = NewParser()
+	p.SetSectionDelimiter("weird", ":")
+	p.SetSectionCommentPrefixes("weird", "//")
+	p.SetSkipErrors(true)
+	if err := p.LoadFromString(input); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]map[string]string{
+		"weird":  {"url": "http://example.com/?a=b", "mode": "fast"},
+		"normal": {"url": "http://example.com/"},
+	}
+	if got := p.GetSections(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSections() = %v, want %v", got, want)
+	}
+
+	wantOut := `name = demo
+
+[weird]
+mode : fast
+url : http://example.com/?a=b
+
+[normal]
+url = http://example.com/
+`
+	if got := p.ToString(); got != wantOut {
+		t.Errorf("ToString() = %q, want %q", got, wantOut)
+	}
+}
+
+func TestSectionDelimiterReset(t *testing.T) {
+	p := NewParser()
+	p.SetSectionDelimiter("s", ":")
+	p.SetSectionDelimiter("s", "")
+	if err := p.LoadFromString("[s]\nkey = value\n"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := p.Get("s", "key"); got != "value" {
+		t.Errorf("Get = %q, want %q", got, "value")
+	}
+}
+
+func TestRequireDelimiterSpacing(t *testing.T) {
+	tests := []struct {
+		style    DelimiterSpacing
+		accepted []string
+		rejected []string
+		written  string
+	}{
+		{DelimiterSpacingAny, []string{"k=v", "k = v", "k =v", "k\t=  v"}, nil, "k = v\n"},
+		{DelimiterSpacingNone, []string{"k=v", "  k=v"}, []string{"k = v", "k =v", "k= v"}, "k=v\n"},
+		{DelimiterSpacingAround, []string{"k = v", "k\t=\tv"}, []string{"k=v", "k =v", "k= v"}, "k = v\n"},
+	}
+
+	for _, tt := range tests {
+		for _, line := range tt.accepted {
+			p := NewParser()
+			p.SetRequireDelimiterSpacing(tt.style)
+			if err := p.LoadFromString(line + "\n"); err != nil {
+				t.Errorf("style %d: %q rejected: %v", tt.style, line, err)
+				continue
+			}
+			if got := p.ToString(); got != tt.written {
+				t.Errorf("style %d: ToString() = %q, want %q", tt.style, got, tt.written)
+			}
+		}
+		for _, line := range tt.rejected {
+			p := NewParser()
+			p.SetRequireDelimiterSpacing(tt.style)
+			err := p.LoadFromString(tt.written + line + "\n")
+			if !errors.Is(err, ErrDelimiterSpacing) || !strings.HasPrefix(err.Error(), "line 2: ") {
+				t.Errorf("style %d: %q gave %v, want %v on line 2", tt.style, line, err, ErrDelimiterSpacing)
+			}
+		}
+	}
+}
+
+func TestSectionDelimiterLint(t *testing.T) {
+	p := NewParser()
+	p.SetSectionDelimiter("w", ":")
+	if err := p.LoadFromString("[w]\nk::v\nurl: =x\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	warnings := p.Lint()
+	if len(warnings) != 1 || warnings[0].Line != 2 || warnings[0].Key != "k" {
+		t.Errorf("Lint() = %v, want one warning for k on line 2", warnings)
+	}
+}
+
+func TestSetSectionCommentPrefixesCopies(t *testing.T) {
+	prefixes := []string{"//"}
+	p := NewParser()
+	p.SetSectionCommentPrefixes("s", prefixes...)
+	prefixes[0] = "--"
+
+	if err := p.LoadFromString("[s]\n// note\nk = v\n"); err != nil {
+		t.Fatalf("LoadFromString: %v", err)
+	}
+	if got, _ := p.Get("s", "k"); got != "v" {
+		t.Errorf("got %q, want %q", got, "v")
+	}
+}
@@ -0,0 +1,55 @@
+package iniparser
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Render runs every value through text/template with data, replacing the
+// stored values with the rendered results, so that greeting=Hello {{.Name}}
+// becomes greeting=Hello Ada. Every value of a key collected by SetMultiKey
+// is rendered, not only the last one. Only keys whose rendered values differ
+// are stored, so keys without template actions keep their default mark and
+// raw source text. Failures are reported per section and key and joined
+// together; if any value fails, no value is changed.
+func (p *Parser) Render(data interface{}) error {
+	entries := p.Entries()
+	original := make([][]string, len(entries))
+	rendered := make([][]string, len(entries))
+	var errs []error
+
+	for i, e := range entries {
+		original[i], _ = p.GetAll(e.Section, e.Key)
+		for _, value := range original[i] {
+			tmpl, err := template.New(qualifiedKey(e.Section, e.Key)).Option("missingkey=error").Parse(value)
+			if err == nil {
+				var b strings.Builder
+				err = tmpl.Execute(&b, data)
+				rendered[i] = append(rendered[i], b.String())
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("[%s] %s: %w", e.Section, e.Key, err))
+				break
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	for i, e := range entries {
+		values := rendered[i]
+		if slices.Equal(values, original[i]) {
+			continue
+		}
+		p.setValue(e.Section, e.Key, values[len(values)-1])
+		if len(values) > 1 {
+			p.storeValues(e.Section, e.Key, values)
+		}
+		p.dirty = true
+	}
+	return nil
+}
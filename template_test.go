@@ -0,0 +1,75 @@
+package iniparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	const input = "[app]\ngreeting = Hello {{.Name}}\nplain = unchanged\n"
+
+	t.Run("struct", func(t *testing.T) {
+		p := NewParser()
+		if err := p.LoadFromString(input); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := p.Render(struct{ Name string }{"Ada"}); err != nil {
+			t.Fatalf("Render: %v", err)
+		}
+		if got, _ := p.Get("app", "greeting"); got != "Hello Ada" {
+			t.Errorf("got %q, want %q", got, "Hello Ada")
+		}
+		if got, _ := p.Get("app", "plain"); got != "unchanged" {
+			t.Errorf("got %q, want %q", got, "unchanged")
+		}
+	})
+
+	t.Run("map", func(t *testing.T) {
+		p := NewParser()
+		if err := p.LoadFromString(input); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := p.Render(map[string]string{"Name": "Grace"}); err != nil {
+			t.Fatalf("Render: %v", err)
+		}
+		if got, _ := p.Get("app", "greeting"); got != "Hello Grace" {
+			t.Errorf("got %q, want %q", got, "Hello Grace")
+		}
+	})
+
+	t.Run("bad template", func(t *testing.T) {
+		p := NewParser()
+		if err := p.LoadFromString(input + "broken = {{.Name\n"); err != nil {
+			t.Fatal(err)
+		}
+
+		err := p.Render(map[string]string{"Name": "Ada"})
+		if err == nil || !strings.Contains(err.Error(), "[app] broken") {
+			t.Fatalf("got %v, want an error naming [app] broken", err)
+		}
+		if got, _ := p.Get("app", "greeting"); got != "Hello {{.Name}}" {
+			t.Errorf("value changed despite the error: %q", got)
+		}
+	})
+}
+
+func TestRenderMultiKey(t *testing.T) {
+	p := NewParser()
+	p.SetMultiKey(true)
+	if err := p.LoadFromString("[s]\nm = {{.A}}\nm = {{.B}}\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Render(map[string]string{"A": "a", "B": "b"}); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if got, _ := p.GetAll("s", "m"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("GetAll = %q, want [a b]", got)
+	}
+	if got, _ := p.Get("s", "m"); got != "b" {
+		t.Errorf("Get = %q, want %q", got, "b")
+	}
+}
@@ -0,0 +1,210 @@
+package iniparser
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// GetRequired returns the value of key in section, or an ErrKeyNotFound
+// error naming the section and key when it is missing.
+func (p *Parser) GetRequired(section, key string) (string, error) {
+	value, ok := p.Get(section, key)
+	if !ok {
+		return "", fmt.Errorf("%w: [%s] %s", ErrKeyNotFound, section, key)
+	}
+	return value, nil
+}
+
+// GetRequiredInt returns the value of key in section as an int.
+func (p *Parser) GetRequiredInt(section, key string) (int, error) {
+	return Get(p, section, key, strconv.Atoi)
+}
+
+// GetRequiredBool returns the value of key in section as a bool. The values
+// true, yes, on and 1 are true and false, no, off and 0 are false, in any
+// case.
+func (p *Parser) GetRequiredBool(section, key string) (bool, error) {
+	return Get(p, section, key, parseBool)
+}
+
+// GetRequiredFloat64 returns the value of key in section as a float64.
+func (p *Parser) GetRequiredFloat64(section, key string) (float64, error) {
+	return Get(p, section, key, func(s string) (float64, error) {
+		return strconv.ParseFloat(s, 64)
+	})
+}
+
+// GetRequiredDuration returns the value of key in section as a
+// time.Duration, written as accepted by time.ParseDuration.
+func (p *Parser) GetRequiredDuration(section, key string) (time.Duration, error) {
+	return Get(p, section, key, time.ParseDuration)
+}
+
+// GetByteSize returns the value of key in section as a number of bytes. The
+// value is a non-negative integer optionally followed, with or without a
+// space, by a case-insensitive unit: B for bytes; K, M, G, T or KB, MB, GB,
+// TB for decimal multiples of 1000; and KiB, MiB, GiB, TiB for binary
+// multiples of 1024. So "512K" is 512000 bytes and "2GiB" is 2147483648.
+func (p *Parser) GetByteSize(section, key string) (int64, error) {
+	return Get(p, section, key, parseByteSize)
+}
+
+var byteSizeUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+func parseByteSize(s string) (int64, error) {
+	digits := strings.TrimLeft(s, "0123456789")
+	number, unit := s[:len(s)-len(digits)], strings.TrimSpace(digits)
+	if number == "" {
+		return 0, fmt.Errorf("%q does not start with a number", s)
+	}
+	multiplier, ok := byteSizeUnits[strings.ToLower(unit)]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit %q", unit)
+	}
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || n > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("%q overflows int64", s)
+	}
+	return n * multiplier, nil
+}
+
+// GetRune returns the value of key in section as a single character, such as
+// a delimiter. The value must be exactly one valid UTF-8 encoded rune.
+func (p *Parser) GetRune(section, key string) (rune, error) {
+	return Get(p, section, key, func(s string) (rune, error) {
+		r, size := utf8.DecodeRuneInString(s)
+		switch {
+		case s == "":
+			return 0, errors.New("empty value")
+		case r == utf8.RuneError && size == 1:
+			return 0, errors.New("invalid UTF-8")
+		case size != len(s):
+			return 0, fmt.Errorf("%q is longer than one character", s)
+		}
+		return r, nil
+	})
+}
+
+// GetIP returns the value of key in section as an IPv4 or IPv6 address, as
+// parsed by net.ParseIP.
+func (p *Parser) GetIP(section, key string) (net.IP, error) {
+	return Get(p, section, key, func(s string) (net.IP, error) {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("%q is not an IP address", s)
+		}
+		return ip, nil
+	})
+}
+
+// GetCIDR returns the value of key in section as a network in CIDR
+// notation, such as "10.0.0.0/8", as parsed by net.ParseCIDR.
+func (p *Parser) GetCIDR(section, key string) (*net.IPNet, error) {
+	return Get(p, section, key, func(s string) (*net.IPNet, error) {
+		_, network, err := net.ParseCIDR(s)
+		return network, err
+	})
+}
+
+// GetURL returns the value of key in section parsed by url.Parse. Values are
+// not cut at a comment prefix, so a fragment such as #section is kept; with
+// SetUnquoteValues the URL may also be written in double quotes.
+func (p *Parser) GetURL(section, key string) (*url.URL, error) {
+	return Get(p, section, key, url.Parse)
+}
+
+// Get returns the value of key in section converted by parse, for types not
+// covered by the typed getters. A missing key yields an ErrKeyNotFound error
+// and a conversion failure an ErrInvalidValue error that also wraps the
+// error returned by parse.
+func Get[T any](p *Parser, section, key string, parse func(string) (T, error)) (T, error) {
+	var zero T
+	value, err := p.GetRequired(section, key)
+	if err != nil {
+		return zero, err
+	}
+	v, err := parse(value)
+	if err != nil {
+		return zero, fmt.Errorf("%w: [%s] %s = %q: %w", ErrInvalidValue, section, key, value, err)
+	}
+	return v, nil
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "on", "1":
+		return true, nil
+	case "false", "no", "off", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("%q is not a boolean", s)
+}
+
+// NormalizeBooleans rewrites every value that GetRequiredBool accepts, such
+// as "yes", "On" or "0", to its canonical "true" or "false" form. Since "1"
+// and "0" count as booleans, numeric values of one or zero are rewritten as
+// well.
+func (p *Parser) NormalizeBooleans() {
+	normalize := func(section string, keys map[string]string) {
+		for key, value := range keys {
+			b, err := parseBool(value)
+			if err != nil {
+				continue
+			}
+			if canonical := strconv.FormatBool(b); canonical != value {
+				keys[key] = canonical
+				delete(p.multiValues[section], key)
+				delete(p.rawValues[section], key)
+				p.dirty = true
+			}
+		}
+	}
+	normalize("", p.globalKeys)
+	for _, section := range p.sections {
+		normalize(section, p.data[section])
+	}
+}
+
+// SetInt stores v in decimal form under section and key.
+func (p *Parser) SetInt(section, key string, v int) error {
+	return p.Set(section, key, strconv.Itoa(v))
+}
+
+// SetBool stores v as "true" or "false" under section and key.
+func (p *Parser) SetBool(section, key string, v bool) error {
+	return p.Set(section, key, strconv.FormatBool(v))
+}
+
+// SetFloat64 stores v under section and key using the shortest
+// representation that parses back to the same value.
+func (p *Parser) SetFloat64(section, key string, v float64) error {
+	return p.Set(section, key, strconv.FormatFloat(v, 'g', -1, 64))
+}
+
+// SetDuration stores v under section and key in the form written by
+// time.Duration.String, such as "1m30s".
+func (p *Parser) SetDuration(section, key string, v time.Duration) error {
+	return p.Set(section, key, v.String())
+}
@@ -0,0 +1,321 @@
+package iniparser
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const typedINI = `[app]
+name = demo
+port = 8080
+debug = Yes
+ratio = 0.75
+timeout = 1m30s
+bad = nope
+`
+
+func loadTyped(t *testing.T) *Parser {
+	t.Helper()
+
+	p := NewParser()
+	if err := p.LoadFromString(typedINI); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestGetRequired(t *testing.T) {
+	p := loadTyped(t)
+
+	if got, err := p.GetRequired("app", "name"); err != nil || got != "demo" {
+		t.Errorf("present: got (%q, %v), want (%q, nil)", got, err, "demo")
+	}
+	if got, err := p.GetRequired("app", "missing"); !errors.Is(err, ErrKeyNotFound) || got != "" {
+		t.Errorf("absent: got (%q, %v), want (\"\", %v)", got, err, ErrKeyNotFound)
+	}
+}
+
+func TestGetRequiredTyped(t *testing.T) {
+	p := loadTyped(t)
+
+	t.Run("present", func(t *testing.T) {
+		if got, err := p.GetRequiredInt("app", "port"); err != nil || got != 8080 {
+			t.Errorf("GetRequiredInt = (%d, %v)", got, err)
+		}
+		if got, err := p.GetRequiredBool("app", "debug"); err != nil || !got {
+			t.Errorf("GetRequiredBool = (%v, %v)", got, err)
+		}
+		if got, err := p.GetRequiredFloat64("app", "ratio"); err != nil || got != 0.75 {
+			t.Errorf("GetRequiredFloat64 = (%v, %v)", got, err)
+		}
+		if got, err := p.GetRequiredDuration("app", "timeout"); err != nil || got != 90*time.Second {
+			t.Errorf("GetRequiredDuration = (%v, %v)", got, err)
+		}
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		if got, err := p.GetRequiredInt("app", "missing"); !errors.Is(err, ErrKeyNotFound) || got != 0 {
+			t.Errorf("GetRequiredInt = (%d, %v)", got, err)
+		}
+		if got, err := p.GetRequiredBool("app", "missing"); !errors.Is(err, ErrKeyNotFound) || got {
+			t.Errorf("GetRequiredBool = (%v, %v)", got, err)
+		}
+		if got, err := p.GetRequiredDuration("app", "missing"); !errors.Is(err, ErrKeyNotFound) || got != 0 {
+			t.Errorf("GetRequiredDuration = (%v, %v)", got, err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := p.GetRequiredInt("app", "bad"); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("GetRequiredInt: got %v, want %v", err, ErrInvalidValue)
+		}
+		_, err := p.GetRequiredBool("app", "bad")
+		if want := `invalid value: [app] bad = "nope": "nope" is not a boolean`; !errors.Is(err, ErrInvalidValue) || err.Error() != want {
+			t.Errorf("GetRequiredBool: got %v, want %q", err, want)
+		}
+		if _, err := p.GetRequiredFloat64("app", "bad"); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("GetRequiredFloat64: got %v, want %v", err, ErrInvalidValue)
+		}
+	})
+}
+
+func TestTypedSetters(t *testing.T) {
+	p := NewParser()
+	if err := p.SetInt("app", "port", 8080); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetBool("app", "enabled", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetFloat64("app", "ratio", 0.1); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetDuration("app", "timeout", 90*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	stored := map[string]string{"port": "8080", "enabled": "true", "ratio": "0.1", "timeout": "1m30s"}
+	for key, want := range stored {
+		if got, _ := p.Get("app", key); got != want {
+			t.Errorf("%s stored as %q, want %q", key, got, want)
+		}
+	}
+
+	if got, err := p.GetRequiredInt("app", "port"); err != nil || got != 8080 {
+		t.Errorf("GetRequiredInt = (%d, %v)", got, err)
+	}
+	if got, err := p.GetRequiredBool("app", "enabled"); err != nil || !got {
+		t.Errorf("GetRequiredBool = (%v, %v)", got, err)
+	}
+	if got, err := p.GetRequiredFloat64("app", "ratio"); err != nil || got != 0.1 {
+		t.Errorf("GetRequiredFloat64 = (%v, %v)", got, err)
+	}
+	if got, err := p.GetRequiredDuration("app", "timeout"); err != nil || got != 90*time.Second {
+		t.Errorf("GetRequiredDuration = (%v, %v)", got, err)
+	}
+}
+
+type endpoint struct {
+	Host, Port string
+}
+
+var errBadEndpoint = errors.New("want host,port")
+
+func parseEndpoint(s string) (endpoint, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return endpoint{}, errBadEndpoint
+	}
+	return endpoint{Host: strings.TrimSpace(parts[0]), Port: strings.TrimSpace(parts[1])}, nil
+}
+
+func TestGenericGet(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString("[db]\nprimary = db1, 5432\nbroken = db2\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Get(p, "db", "primary", parseEndpoint)
+	if want := (endpoint{"db1", "5432"}); err != nil || got != want {
+		t.Errorf("got (%+v, %v), want (%+v, nil)", got, err, want)
+	}
+
+	_, err = Get(p, "db", "broken", parseEndpoint)
+	if !errors.Is(err, ErrInvalidValue) || !errors.Is(err, errBadEndpoint) {
+		t.Errorf("got %v, want an error wrapping %v and %v", err, ErrInvalidValue, errBadEndpoint)
+	}
+
+	if _, err := Get(p, "db", "missing", parseEndpoint); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("got %v, want %v", err, ErrKeyNotFound)
+	}
+}
+
+func TestNormalizeBooleans(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString(`debug = on
+[flags]
+a = yes
+b = On
+c = 1
+d = no
+e = off
+f = 0
+g = true
+h = maybe
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	p.NormalizeBooleans()
+
+	want := map[string]string{
+		"a": "true", "b": "true", "c": "true",
+		"d": "false", "e": "false", "f": "false",
+		"g": "true", "h": "maybe",
+	}
+	if got := p.GetSections()["flags"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("flags = %v, want %v", got, want)
+	}
+	if got, _ := p.Get("", "debug"); got != "true" {
+		t.Errorf("debug = %q, want %q", got, "true")
+	}
+	if !p.IsDirty() {
+		t.Error("IsDirty() = false after normalizing")
+	}
+}
+
+func TestGetByteSize(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString(`[sizes]
+plain = 100
+bytes = 64B
+k = 512K
+mb = 10MB
+g = 2G
+kib = 4KiB
+spaced = 1 mib
+gib = 2gib
+fraction = 1.5MB
+unit = 10XB
+none = MB
+huge = 9999999TiB
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]int64{
+		"plain":  100,
+		"bytes":  64,
+		"k":      512000,
+		"mb":     10000000,
+		"g":      2000000000,
+		"kib":    4096,
+		"spaced": 1048576,
+		"gib":    2147483648,
+	} {
+		if got, err := p.GetByteSize("sizes", key); err != nil || got != want {
+			t.Errorf("GetByteSize(%s) = (%d, %v), want %d", key, got, err, want)
+		}
+	}
+
+	for _, key := range []string{"fraction", "unit", "none", "huge"} {
+		if _, err := p.GetByteSize("sizes", key); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("GetByteSize(%s) error = %v, want %v", key, err, ErrInvalidValue)
+		}
+	}
+}
+
+func TestGetIPAndCIDR(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString(`[net]
+v4 = 192.168.1.10
+v6 = 2001:db8::1
+cidr = 10.0.0.0/8
+bad_ip = 300.1.1.1
+bad_cidr = 10.0.0.0/33
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := p.GetIP("net", "v4"); err != nil || !got.Equal(net.IPv4(192, 168, 1, 10)) {
+		t.Errorf("GetIP(v4) = (%v, %v)", got, err)
+	}
+	if got, err := p.GetIP("net", "v6"); err != nil || got.String() != "2001:db8::1" {
+		t.Errorf("GetIP(v6) = (%v, %v)", got, err)
+	}
+	got, err := p.GetCIDR("net", "cidr")
+	if err != nil || got.String() != "10.0.0.0/8" {
+		t.Errorf("GetCIDR(cidr) = (%v, %v)", got, err)
+	}
+	if err == nil && !got.Contains(net.IPv4(10, 1, 2, 3)) {
+		t.Errorf("%v does not contain 10.1.2.3", got)
+	}
+
+	for _, key := range []string{"bad_ip", "cidr"} {
+		if _, err := p.GetIP("net", key); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("GetIP(%s) error = %v, want %v", key, err, ErrInvalidValue)
+		}
+	}
+	for _, key := range []string{"bad_cidr", "v4"} {
+		if _, err := p.GetCIDR("net", key); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("GetCIDR(%s) error = %v, want %v", key, err, ErrInvalidValue)
+		}
+	}
+}
+
+func TestGetRune(t *testing.T) {
+	p := NewParser()
+	p.SetAllowEmptyValues(true)
+	if err := p.LoadFromString("[csv]\nsep = ;\nquote = «\nempty =\nlong = ab\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]rune{"sep": ';', "quote": '«'} {
+		if got, err := p.GetRune("csv", key); err != nil || got != want {
+			t.Errorf("GetRune(%s) = (%q, %v), want %q", key, got, err, want)
+		}
+	}
+	for _, key := range []string{"empty", "long"} {
+		if _, err := p.GetRune("csv", key); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("GetRune(%s) error = %v, want %v", key, err, ErrInvalidValue)
+		}
+	}
+}
+
+func TestGetURL(t *testing.T) {
+	p := NewParser()
+	p.SetUnquoteValues(true)
+	if err := p.LoadFromString(`[api]
+base = https://api.example.com:8443/v1?debug=1
+docs = https://example.com/guide#install
+quoted = "https://example.com/a b#top"
+bad = http://[::1
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct{ key, host, path, fragment string }{
+		{"base", "api.example.com:8443", "/v1", ""},
+		{"docs", "example.com", "/guide", "install"},
+		{"quoted", "example.com", "/a b", "top"},
+	}
+	for _, tt := range tests {
+		u, err := p.GetURL("api", tt.key)
+		if err != nil {
+			t.Errorf("GetURL(%s): %v", tt.key, err)
+			continue
+		}
+		if u.Host != tt.host || u.Path != tt.path || u.Fragment != tt.fragment {
+			t.Errorf("GetURL(%s) = host %q path %q fragment %q, want %q %q %q",
+				tt.key, u.Host, u.Path, u.Fragment, tt.host, tt.path, tt.fragment)
+		}
+	}
+
+	if _, err := p.GetURL("api", "bad"); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("GetURL(bad) error = %v, want %v", err, ErrInvalidValue)
+	}
+}
@@ -0,0 +1,87 @@
+package iniparser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrInvalidTarget is returned by Unmarshal when v is not a non-nil
+	// pointer to a struct.
+	ErrInvalidTarget = errors.New("target must be a non-nil pointer to a struct")
+	// ErrUnsupportedType is returned by Unmarshal for fields it cannot fill
+	// and by CheckTypes for type names it does not know.
+	ErrUnsupportedType = errors.New("unsupported field type")
+)
+
+// Unmarshal copies the parsed values into the struct pointed to by v. Only
+// fields carrying an `ini` tag are considered:
+//
+//   - string fields are filled from the global key named by the tag
+//   - struct fields are filled from the section named by the tag, matching
+//     their own string fields against keys by tag
+//   - map[string]string fields receive a copy of every key in the section
+//     named by the tag, which suits sections with arbitrary keys
+//
+// Missing sections and keys leave the field untouched.
+func (p *Parser) Unmarshal(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrInvalidTarget
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name, ok := field.Tag.Lookup("ini")
+		if !ok || name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+
+		fv := rv.Field(i)
+		switch {
+		case field.Type.Kind() == reflect.String:
+			if value, ok := p.Get("", name); ok {
+				fv.SetString(value)
+			}
+		case field.Type.Kind() == reflect.Struct:
+			if err := p.unmarshalSection(name, fv); err != nil {
+				return err
+			}
+		case field.Type == reflect.TypeOf(map[string]string(nil)):
+			if keys, ok := p.data[name]; ok {
+				fv.Set(reflect.ValueOf(copyMap(keys)))
+			}
+		default:
+			return fmt.Errorf("%w: field %s has type %s", ErrUnsupportedType, field.Name, field.Type)
+		}
+	}
+
+	return nil
+}
+
+func (p *Parser) unmarshalSection(section string, rv reflect.Value) error {
+	keys, ok := p.data[section]
+	if !ok {
+		return nil
+	}
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name, ok := field.Tag.Lookup("ini")
+		if !ok || name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		if field.Type.Kind() != reflect.String {
+			return fmt.Errorf("%w: field %s.%s has type %s", ErrUnsupportedType, rt.Name(), field.Name, field.Type)
+		}
+		if value, ok := keys[name]; ok {
+			rv.Field(i).SetString(value)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package iniparser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshal(t *testing.T) {
+	p := NewParser()
+	err := p.LoadFromString(`name = demo
+
+[server]
+host = localhost
+port = 8080
+
+[labels]
+team = core
+env = prod
+region = eu
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type server struct {
+		Host string `ini:"host"`
+		Port string `ini:"port"`
+	}
+	var cfg struct {
+		Name    string            `ini:"name"`
+		Server  server            `ini:"server"`
+		Labels  map[string]string `ini:"labels"`
+		Missing map[string]string `ini:"missing"`
+	}
+
+	if err := p.Unmarshal(&cfg); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if cfg.Name != "demo" {
+		t.Errorf("Name = %q, want %q", cfg.Name, "demo")
+	}
+	if want := (server{Host: "localhost", Port: "8080"}); cfg.Server != want {
+		t.Errorf("Server = %+v, want %+v", cfg.Server, want)
+	}
+	wantLabels := map[string]string{"team": "core", "env": "prod", "region": "eu"}
+	if !reflect.DeepEqual(cfg.Labels, wantLabels) {
+		t.Errorf("Labels = %v, want %v", cfg.Labels, wantLabels)
+	}
+	if cfg.Missing != nil {
+		t.Errorf("Missing = %v, want nil", cfg.Missing)
+	}
+}
+
+func TestUnmarshalInvalidTarget(t *testing.T) {
+	p := loadSample(t)
+
+	var cfg struct{}
+	if err := p.Unmarshal(cfg); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("got %v, want %v", err, ErrInvalidTarget)
+	}
+
+	var bad struct {
+		Server map[string]int `ini:"server"`
+	}
+	if err := p.Unmarshal(&bad); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("got %v, want %v", err, ErrUnsupportedType)
+	}
+}
@@ -0,0 +1,66 @@
+package iniparser
+
+import (
+	"errors"
+	"fmt"
+)
+
+type validator struct {
+	section, key string
+	fn           func(value string) error
+}
+
+// AddValidator registers fn to check the value of key in section when
+// Validate runs. An empty section refers to the global keys.
+func (p *Parser) AddValidator(section, key string, fn func(value string) error) {
+	p.validators = append(p.validators, validator{section: section, key: key, fn: fn})
+}
+
+// Validate runs every registered validator against the current values and
+// returns all failures joined together, each prefixed with its section and
+// key. Validators whose key is missing are skipped.
+func (p *Parser) Validate() error {
+	var errs []error
+	for _, v := range p.validators {
+		value, ok := p.Get(v.section, v.key)
+		if !ok {
+			continue
+		}
+		if err := v.fn(value); err != nil {
+			errs = append(errs, fmt.Errorf("[%s] %s: %w", v.section, v.key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckTypes checks that every key named in spec, a map from section to key
+// to type name, exists and holds a value of that type. The type names are
+// "int", "bool", "float" and "duration", read as by GetRequiredInt,
+// GetRequiredBool, GetRequiredFloat64 and GetRequiredDuration. All failures
+// are returned joined together, sorted by section and key; an unknown type
+// name fails with ErrUnsupportedType.
+func (p *Parser) CheckTypes(spec map[string]map[string]string) error {
+	var errs []error
+	for _, section := range sortedKeys(spec) {
+		keys := spec[section]
+		for _, key := range sortedKeys(keys) {
+			var err error
+			switch typ := keys[key]; typ {
+			case "int":
+				_, err = p.GetRequiredInt(section, key)
+			case "bool":
+				_, err = p.GetRequiredBool(section, key)
+			case "float":
+				_, err = p.GetRequiredFloat64(section, key)
+			case "duration":
+				_, err = p.GetRequiredDuration(section, key)
+			default:
+				err = fmt.Errorf("%w %q for [%s] %s", ErrUnsupportedType, typ, section, key)
+			}
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
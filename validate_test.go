@@ -0,0 +1,85 @@
+package iniparser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var errPortRange = errors.New("port out of range")
+
+func validPort(value string) error {
+	port, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("%w: %d", errPortRange, port)
+	}
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("passing validator", func(t *testing.T) {
+		p := loadSample(t)
+		p.AddValidator("server", "port", validPort)
+
+		if err := p.Validate(); err != nil {
+			t.Errorf("got %v, want nil", err)
+		}
+	})
+
+	t.Run("failing validator", func(t *testing.T) {
+		p := loadSample(t)
+		p.AddValidator("server", "port", validPort)
+		if err := p.Set("server", "port", "70000"); err != nil {
+			t.Fatal(err)
+		}
+
+		err := p.Validate()
+		if !errors.Is(err, errPortRange) {
+			t.Fatalf("got %v, want %v", err, errPortRange)
+		}
+		if !strings.Contains(err.Error(), "[server] port") {
+			t.Errorf("error %q lacks section/key context", err)
+		}
+	})
+
+	t.Run("missing key is skipped", func(t *testing.T) {
+		p := loadSample(t)
+		p.AddValidator("server", "timeout", func(string) error {
+			return errors.New("should not run")
+		})
+
+		if err := p.Validate(); err != nil {
+			t.Errorf("got %v, want nil", err)
+		}
+	})
+}
+
+func TestCheckTypes(t *testing.T) {
+	p := NewParser()
+	if err := p.LoadFromString(typedINI); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := map[string]map[string]string{
+		"app": {"port": "int", "debug": "bool", "ratio": "float", "timeout": "duration"},
+	}
+	if err := p.CheckTypes(valid); err != nil {
+		t.Errorf("valid spec: got %v, want nil", err)
+	}
+
+	err := p.CheckTypes(map[string]map[string]string{
+		"app": {"port": "bool", "debug": "bool", "missing": "int", "ratio": "uuid"},
+	})
+	if !errors.Is(err, ErrInvalidValue) || !errors.Is(err, ErrKeyNotFound) || !errors.Is(err, ErrUnsupportedType) {
+		t.Fatalf("got %v, want errors for each failing key", err)
+	}
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[1], "[app] port") {
+		t.Errorf("got %q, want three failures sorted by key", lines)
+	}
+}